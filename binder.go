@@ -0,0 +1,400 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/Chise1/openapi/validate"
+)
+
+var errNilHandler = errors.New("openapi: nil handler")
+
+// binder 把请求解码为路由声明的类型,并按声明的content type写出响应
+type binder struct {
+	codecs   *codecs
+	para     reflect.Type                     //GetReqPara的类型,没有时为nil
+	bodies   map[string]reflect.Type          //媒体类型 -> GetReqBody的类型
+	statuses map[reflect.Type]int             //响应body的类型 -> 状态码
+	media    map[reflect.Type]map[string]bool //响应body的类型 -> 声明的媒体类型
+}
+
+func newBinder(route RouteStruct, codecs *codecs) *binder {
+	n := &binder{
+		codecs:   codecs,
+		bodies:   map[string]reflect.Type{},
+		statuses: map[reflect.Type]int{},
+		media:    map[reflect.Type]map[string]bool{},
+	}
+	if v := route.GetReqPara(); v != nil {
+		n.para = indirectType(reflect.TypeOf(v))
+	}
+	if v := route.GetReqBody(); v != nil {
+		for contentType, body := range bodyContent(v) {
+			n.bodies[string(contentType)] = indirectType(reflect.TypeOf(body))
+		}
+	}
+	for status, v := range route.GetResBody() {
+		if v == nil || !isStatusCode(status) {
+			continue
+		}
+		for contentType, body := range bodyContent(v) {
+			t := indirectType(reflect.TypeOf(body))
+			// 多个状态码使用同一个类型时使用最小的状态码
+			if old, ok := n.statuses[t]; !ok || status < old {
+				n.statuses[t] = status
+			}
+			if n.media[t] == nil {
+				n.media[t] = map[string]bool{}
+			}
+			n.media[t][string(contentType)] = true
+		}
+	}
+	return n
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// bind 解码请求的参数和body,返回指向新值的指针
+func (n *binder) bind(validator *validate.Validator, spec *models.OpenAPI, oper *models.Operation, vars map[string]string, r *http.Request) (interface{}, interface{}, *Problem) {
+	var para, body interface{}
+	if n.para != nil {
+		v := reflect.New(n.para).Interface()
+		if problem := bindParams(spec, oper, vars, r, v); problem != nil {
+			return nil, nil, problem
+		}
+		para = v
+	}
+	if len(n.bodies) > 0 && oper.RequestBody != nil {
+		v, problem := n.bindBody(validator, spec, oper.RequestBody, r)
+		if problem != nil {
+			return nil, nil, problem
+		}
+		body = v
+	}
+	return para, body, nil
+}
+
+// bindParams 按参数的schema转换path,query,header,cookie中的值,再解码到v
+func bindParams(spec *models.OpenAPI, oper *models.Operation, vars map[string]string, r *http.Request, v interface{}) *Problem {
+	values := map[string]interface{}{}
+	var invalid []InvalidParam
+	source := newParamSource(r, vars)
+	for _, param := range oper.Parameters {
+		value, ok, err := source.value(spec.Components.Schemas, param)
+		if !ok {
+			continue
+		}
+		if err != nil {
+			invalid = append(invalid, InvalidParam{Name: param.Name, In: param.In, Reason: err.Error()})
+			continue
+		}
+		values[param.Name] = value
+	}
+	if len(invalid) == 0 {
+		if err := remarshal(values, v); err != nil {
+			return NewProblem(http.StatusBadRequest, err.Error())
+		}
+		return nil
+	}
+	problem := NewProblem(http.StatusBadRequest, "request validation failed")
+	problem.InvalidParams = invalid
+	return problem
+}
+
+// bindBody 按请求的Content-Type选择body的类型和解码方式,请求中没有body时返回nil.
+// 表单转换类型后按body的schema校验,multipart/form-data直接从请求中流式解析,
+// 解析出的表单保存在r.MultipartForm中,由调用方在handler返回后删除临时文件
+func (n *binder) bindBody(v *validate.Validator, spec *models.OpenAPI, body *models.RequestBody, r *http.Request) (interface{}, *Problem) {
+	empty, err := emptyBody(r)
+	if err != nil {
+		return nil, readBodyProblem(err)
+	}
+	if empty {
+		return nil, nil
+	}
+	contentType, key, media := matchContentType(body.Content, r.Header.Get("Content-Type"))
+	t, ok := n.bodies[key]
+	if media == nil || !ok {
+		return nil, NewProblem(http.StatusUnsupportedMediaType, "unsupported content type "+contentType)
+	}
+	value := reflect.New(t).Interface()
+	if decode, ok := n.codecs.decoder(contentType); ok {
+		if err := decode(r.Body, value); err != nil {
+			if problem := tooLargeProblem(err); problem != nil {
+				return nil, problem
+			}
+			return nil, NewProblem(http.StatusBadRequest, "invalid "+contentType+": "+err.Error())
+		}
+		return value, nil
+	}
+	var form url.Values
+	var files map[string][]*multipart.FileHeader
+	switch contentType {
+	case string(Form):
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, readBodyProblem(err)
+		}
+		if form, err = url.ParseQuery(string(b)); err != nil {
+			return nil, NewProblem(http.StatusBadRequest, "invalid form: "+err.Error())
+		}
+	case string(FormData):
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		multipartForm, err := multipart.NewReader(r.Body, params["boundary"]).ReadForm(maxFormMemory)
+		if err != nil {
+			if problem := tooLargeProblem(err); problem != nil {
+				return nil, problem
+			}
+			return nil, NewProblem(http.StatusBadRequest, "invalid multipart form: "+err.Error())
+		}
+		r.MultipartForm = multipartForm
+		form, files = multipartForm.Value, multipartForm.File
+	default:
+		return nil, NewProblem(http.StatusUnsupportedMediaType, "cannot decode content type "+contentType)
+	}
+	values, invalid := formValues(spec.Components.Schemas, media.Schema, form)
+	if len(invalid) == 0 {
+		invalid = validateForm(v, spec.Components.Schemas, media.Schema, values, files)
+	}
+	if len(invalid) > 0 {
+		problem := NewProblem(http.StatusBadRequest, "request validation failed")
+		problem.InvalidParams = invalid
+		return nil, problem
+	}
+	// 按字段的json名字解码,表单中没有对应字段的值忽略
+	fields := formFields(t)
+	if fields != nil {
+		named := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if value, ok := values[f.name]; ok {
+				named[f.json] = value
+			}
+		}
+		values = named
+	}
+	if err := remarshal(values, value); err != nil {
+		return nil, NewProblem(http.StatusBadRequest, err.Error())
+	}
+	setFiles(reflect.ValueOf(value), fields, files)
+	return value, nil
+}
+
+// validateForm 按body的schema校验转换后的表单,上传的文件按文件名校验,schema中没有的字段忽略
+func validateForm(v *validate.Validator, schemas map[string]*models.Schema, schema *models.Schema, values map[string]interface{}, files map[string][]*multipart.FileHeader) []InvalidParam {
+	resolved := resolveSchema(schemas, schema)
+	instance := map[string]interface{}{}
+	for name, value := range values {
+		if propertySchema(resolved, name) != nil {
+			instance[name] = value
+		}
+	}
+	for name, headers := range files {
+		property := resolveSchema(schemas, propertySchema(resolved, name))
+		if property == nil || len(headers) == 0 {
+			continue
+		}
+		if property.Type != "array" {
+			instance[name] = headers[0].Filename
+			continue
+		}
+		names := make([]interface{}, 0, len(headers))
+		for _, h := range headers {
+			names = append(names, h.Filename)
+		}
+		instance[name] = names
+	}
+	var invalid []InvalidParam
+	for _, e := range v.Validate(schema, instance) {
+		invalid = append(invalid, InvalidParam{Name: e.Pointer, In: "body", Reason: e.Message})
+	}
+	return invalid
+}
+
+// formValues 按body的schema转换表单中的值
+func formValues(schemas map[string]*models.Schema, schema *models.Schema, form url.Values) (map[string]interface{}, []InvalidParam) {
+	schema = resolveSchema(schemas, schema)
+	values := map[string]interface{}{}
+	var invalid []InvalidParam
+	for name, raw := range form {
+		var property *models.Schema
+		if schema != nil && schema.Properties != nil {
+			p, _ := schema.Properties.Get(name)
+			property, _ = p.(*models.Schema)
+		}
+		if property == nil {
+			values[name] = raw[0]
+			continue
+		}
+		value, err := coerceParam(schemas, property, raw)
+		if err != nil {
+			invalid = append(invalid, InvalidParam{Name: "/" + name, In: "body", Reason: err.Error()})
+			continue
+		}
+		values[name] = value
+	}
+	return values, invalid
+}
+
+// remarshal 通过json把values转换为v的类型,字段名按json tag对应
+func remarshal(values interface{}, v interface{}) error {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// write 写出handler的返回值,body为nil时返回204
+func (n *binder) write(w http.ResponseWriter, r *http.Request, spec *models.OpenAPI, oper *models.Operation, para, body interface{}) {
+	if err, ok := body.(error); ok && !n.declared(body) {
+		problem, ok := err.(*Problem)
+		if !ok {
+			// 不把内部错误的细节返回给调用方
+			problem = NewProblem(http.StatusInternalServerError, "")
+		}
+		problem.Instance = r.URL.Path
+		problem.ServeHTTP(w, r)
+		return
+	}
+	writeHeaders(w.Header(), para)
+	if body == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	status := n.status(body)
+	contentType, ok := n.responseContentType(spec.Components, oper, status, body, r.Header.Get("Accept"))
+	if !ok {
+		NewProblem(http.StatusNotAcceptable, "").ServeHTTP(w, r)
+		return
+	}
+	b, err := n.encodeBody(contentType, body)
+	if err != nil {
+		NewProblem(http.StatusInternalServerError, "").ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func (n *binder) declared(body interface{}) bool {
+	_, ok := n.statuses[indirectType(reflect.TypeOf(body))]
+	return ok
+}
+
+// status 按body的类型查找声明的状态码,没有声明时返回200
+func (n *binder) status(body interface{}) int {
+	if status, ok := n.statuses[indirectType(reflect.TypeOf(body))]; ok {
+		return status
+	}
+	return http.StatusOK
+}
+
+// responseContentType 按Accept头在响应声明的媒体类型中选择,优先使用为body的类型声明的媒体类型.
+// 响应没有声明媒体类型时使用body的IContentType或者json,Accept都不能接受时返回false
+func (n *binder) responseContentType(components *models.Components, oper *models.Operation, status int, body interface{}, accept string) (string, bool) {
+	res, _ := findResponse(components, oper.Responses, status)
+	if res == nil || len(res.Content) == 0 {
+		if v, ok := body.(IContentType); ok {
+			return string(v.GetContentType()), true
+		}
+		return string(Json), true
+	}
+	content := Content{}
+	declared := n.media[indirectType(reflect.TypeOf(body))]
+	for contentType := range res.Content {
+		if declared[contentType] {
+			content[ContentType(contentType)] = nil
+		}
+	}
+	if len(content) == 0 {
+		for contentType := range res.Content {
+			content[ContentType(contentType)] = nil
+		}
+	}
+	offers := make([]string, 0, len(content))
+	for _, t := range content.sortedTypes() {
+		offers = append(offers, string(t))
+	}
+	return negotiate(accept, offers)
+}
+
+// encodeBody 按content type编码body,实现了IBody时使用IBody.Marshal,其次使用SetEncoder设置的编码函数
+func (n *binder) encodeBody(contentType string, body interface{}) ([]byte, error) {
+	if v, ok := body.(IBody); ok {
+		return v.Marshal()
+	}
+	if encode, ok := n.codecs.encoder(contentType); ok {
+		var buf bytes.Buffer
+		if err := encode(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	switch v := body.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	if isJSON(contentType) {
+		return json.Marshal(body)
+	}
+	return nil, fmt.Errorf("openapi: cannot encode %T as %s", body, contentType)
+}
+
+// writeHeaders 把v中带header tag的字段写入响应头,零值不写
+func writeHeaders(h http.Header, v interface{}) {
+	if v == nil {
+		return
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _ := headerTag(f.Tag.Get("header"))
+		if f.PkgPath != "" || name == "" || name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				h.Add(name, headerValue(fv.Index(j)))
+			}
+			continue
+		}
+		h.Set(name, headerValue(fv))
+	}
+}
+
+func headerValue(v reflect.Value) string {
+	v = reflect.Indirect(v)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return fmt.Sprint(v.Interface())
+}
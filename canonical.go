@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/iancoleman/orderedmap"
+)
+
+// Canonical 返回文档的规范形式,同一个文档每次序列化的结果都相同,可以提交到git并在CI中检查差异:
+// schema的required按名字排序并去重,path和operation的parameters按in和name排序,
+// components中的schemas,responses,parameters,headers,requestBodies和callbacks按同样的规则处理.
+// paths,components和responses等map序列化时按key排序,状态码的顺序为1XX,200,2XX,...,default.
+// 返回值是新的对象,不会修改spec
+func Canonical(spec *models.OpenAPI) *models.OpenAPI {
+	c := canonicalizer{schemas: map[*models.Schema]*models.Schema{}}
+	s := copySpec(spec)
+	for path, item := range s.Paths {
+		s.Paths[path] = c.pathItem(item)
+	}
+	if s.Components != nil {
+		for name, schema := range s.Components.Schemas {
+			s.Components.Schemas[name] = c.schema(schema)
+		}
+		for name, res := range s.Components.Responses {
+			s.Components.Responses[name] = c.response(res)
+		}
+		if params := s.Components.Parameters; params != nil {
+			s.Components.Parameters = make(map[string]*models.Parameter, len(params))
+			for name, param := range params {
+				s.Components.Parameters[name] = c.parameter(param)
+			}
+		}
+		if headers := s.Components.Headers; headers != nil {
+			s.Components.Headers = make(map[string]*models.Header, len(headers))
+			for name, header := range headers {
+				s.Components.Headers[name] = c.header(header)
+			}
+		}
+		if bodies := s.Components.RequestBodies; bodies != nil {
+			s.Components.RequestBodies = make(map[string]*models.RequestBody, len(bodies))
+			for name, body := range bodies {
+				s.Components.RequestBodies[name] = c.requestBody(body)
+			}
+		}
+		if callbacks := s.Components.Callbacks; callbacks != nil {
+			s.Components.Callbacks = make(map[string]*models.PathItem, len(callbacks))
+			for name, item := range callbacks {
+				s.Components.Callbacks[name] = c.pathItem(item)
+			}
+		}
+	}
+	return s
+}
+
+// CanonicalJSON 返回规范形式的json,两个空格缩进,以换行结尾
+func (n *Document) CanonicalJSON() ([]byte, error) {
+	b, err := json.MarshalIndent(Canonical(n.Snapshot()), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// CanonicalYAML 返回规范形式的yaml
+func (n *Document) CanonicalYAML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Canonical(n.Snapshot()).WriteYAML(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalizer 复制文档中需要排序的部分,同一个schema只复制一次
+type canonicalizer struct {
+	schemas map[*models.Schema]*models.Schema
+}
+
+func (n *canonicalizer) pathItem(item *models.PathItem) *models.PathItem {
+	if item == nil {
+		return nil
+	}
+	copied := *item
+	copied.Parameters = n.parameters(item.Parameters)
+	for _, method := range models.Methods {
+		if oper := item.GetOperation(method); oper != nil {
+			copied.SetOperation(method, n.operation(oper))
+		}
+	}
+	return &copied
+}
+
+func (n *canonicalizer) operation(oper *models.Operation) *models.Operation {
+	copied := *oper
+	copied.Parameters = n.parameters(oper.Parameters)
+	copied.RequestBody = n.requestBody(oper.RequestBody)
+	if oper.Responses != nil {
+		copied.Responses = make(map[string]*models.Response, len(oper.Responses))
+		for status, res := range oper.Responses {
+			copied.Responses[status] = n.response(res)
+		}
+	}
+	if oper.Callbacks != nil {
+		copied.Callbacks = make(map[string]*models.PathItem, len(oper.Callbacks))
+		for name, item := range oper.Callbacks {
+			copied.Callbacks[name] = n.pathItem(item)
+		}
+	}
+	return &copied
+}
+
+func (n *canonicalizer) requestBody(body *models.RequestBody) *models.RequestBody {
+	if body == nil {
+		return nil
+	}
+	copied := *body
+	copied.Content = n.content(body.Content)
+	return &copied
+}
+
+// parameters 复制参数并按in和name排序
+func (n *canonicalizer) parameters(params []*models.Parameter) []*models.Parameter {
+	if params == nil {
+		return nil
+	}
+	copied := make([]*models.Parameter, 0, len(params))
+	for _, param := range params {
+		copied = append(copied, n.parameter(param))
+	}
+	sort.SliceStable(copied, func(i, j int) bool {
+		a, b := copied[i], copied[j]
+		if a == nil || b == nil {
+			return b != nil
+		}
+		if a.In != b.In {
+			return a.In < b.In
+		}
+		return a.Name < b.Name
+	})
+	return copied
+}
+
+func (n *canonicalizer) parameter(param *models.Parameter) *models.Parameter {
+	if param == nil {
+		return nil
+	}
+	copied := *param
+	copied.Schema = n.schema(param.Schema)
+	copied.Content = n.content(param.Content)
+	return &copied
+}
+
+func (n *canonicalizer) response(res *models.Response) *models.Response {
+	if res == nil {
+		return nil
+	}
+	copied := *res
+	copied.Content = n.content(res.Content)
+	if res.Headers != nil {
+		copied.Headers = make(map[string]*models.Header, len(res.Headers))
+		for name, header := range res.Headers {
+			copied.Headers[name] = n.header(header)
+		}
+	}
+	return &copied
+}
+
+func (n *canonicalizer) header(header *models.Header) *models.Header {
+	if header == nil {
+		return nil
+	}
+	copied := *header
+	copied.Schema = n.schema(header.Schema)
+	copied.Content = n.content(header.Content)
+	return &copied
+}
+
+func (n *canonicalizer) content(content map[string]*models.MediaType) map[string]*models.MediaType {
+	if content == nil {
+		return nil
+	}
+	copied := make(map[string]*models.MediaType, len(content))
+	for contentType, media := range content {
+		if media == nil {
+			copied[contentType] = nil
+			continue
+		}
+		m := *media
+		m.Schema = n.schema(media.Schema)
+		if media.Encoding != nil {
+			m.Encoding = make(map[string]*models.Encoding, len(media.Encoding))
+			for name, encoding := range media.Encoding {
+				e := *encoding
+				if encoding.Headers != nil {
+					e.Headers = make(map[string]*models.Header, len(encoding.Headers))
+					for header, h := range encoding.Headers {
+						e.Headers[header] = n.header(h)
+					}
+				}
+				m.Encoding[name] = &e
+			}
+		}
+		copied[contentType] = &m
+	}
+	return copied
+}
+
+func (n *canonicalizer) schema(schema *models.Schema) *models.Schema {
+	if schema == nil {
+		return nil
+	}
+	if copied, ok := n.schemas[schema]; ok {
+		return copied
+	}
+	copied := *schema
+	n.schemas[schema] = &copied
+	copied.Required = sortedRequired(schema.Required)
+	copied.Items = n.schema(schema.Items)
+	copied.Not = n.schema(schema.Not)
+	copied.Media = n.schema(schema.Media)
+	copied.AllOf = n.schemaList(schema.AllOf)
+	copied.OneOf = n.schemaList(schema.OneOf)
+	copied.AnyOf = n.schemaList(schema.AnyOf)
+	if schema.PatternProperties != nil {
+		copied.PatternProperties = make(map[string]*models.Schema, len(schema.PatternProperties))
+		for pattern, s := range schema.PatternProperties {
+			copied.PatternProperties[pattern] = n.schema(s)
+		}
+	}
+	if schema.Properties != nil {
+		// properties保持声明的顺序
+		copied.Properties = orderedmap.New()
+		for _, name := range schema.Properties.Keys() {
+			v, _ := schema.Properties.Get(name)
+			if s, ok := v.(*models.Schema); ok {
+				copied.Properties.Set(name, n.schema(s))
+			} else {
+				copied.Properties.Set(name, v)
+			}
+		}
+	}
+	return &copied
+}
+
+func (n *canonicalizer) schemaList(schemas []*models.Schema) []*models.Schema {
+	if schemas == nil {
+		return nil
+	}
+	copied := make([]*models.Schema, len(schemas))
+	for i, s := range schemas {
+		copied[i] = n.schema(s)
+	}
+	return copied
+}
+
+// sortedRequired 返回排序并去重后的required
+func sortedRequired(required []string) []string {
+	if len(required) == 0 {
+		return required
+	}
+	sorted := append([]string(nil), required...)
+	sort.Strings(sorted)
+	unique := sorted[:1]
+	for _, name := range sorted[1:] {
+		if name != unique[len(unique)-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type CanonicalPara struct {
+	Zone  string `json:"zone" in:"header"`
+	Limit int    `json:"limit,omitempty" in:"query"`
+	After string `json:"after,omitempty" in:"query"`
+	ID    int    `json:"id" in:"path"`
+}
+
+type CanonicalBody struct {
+	Zebra string `json:"zebra"`
+	Apple string `json:"apple"`
+}
+
+type StableRouter struct {
+	TestRouter
+}
+
+// GetResBody 两个状态码的body是同一个包内不同函数中同名的类型,生成的component需要改名
+func (n *StableRouter) GetResBody() map[int]interface{} {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	return map[int]interface{}{200: Item{}, 201: otherItem(), StatusDefault: ErrorBody{}}
+}
+
+func otherItem() interface{} {
+	type Item struct {
+		Code int `json:"code"`
+	}
+	return Item{}
+}
+
+func TestCanonical(t *testing.T) {
+	doc := NewDocument("canonical", "1.0.0")
+	doc.Register(&TestRouter{Method: "PUT", Path: "/items/{id}", Param: CanonicalPara{}, ReqStruct: CanonicalBody{}})
+	spec := doc.Snapshot()
+	canonical := Canonical(spec)
+
+	var names []string
+	for _, p := range canonical.Paths["/items/{id}"].Put.Parameters {
+		names = append(names, p.In+":"+p.Name)
+	}
+	require.Equal(t, []string{"header:zone", "path:id", "query:after", "query:limit"}, names)
+	require.Equal(t, "zone", spec.Paths["/items/{id}"].Put.Parameters[0].Name)
+
+	require.Equal(t, []string{"apple", "zebra"}, canonical.Components.Schemas["CanonicalBody"].Required)
+	require.Equal(t, []string{"zebra", "apple"}, spec.Components.Schemas["CanonicalBody"].Required)
+	require.Equal(t, []string{"zebra", "apple"}, canonical.Components.Schemas["CanonicalBody"].Properties.Keys())
+	require.Equal(t, []string{"a", "b"}, sortedRequired([]string{"b", "a", "b"}))
+
+	// components中的参数,头和请求body同样处理
+	object := func() *models.Schema {
+		return &models.Schema{Type: "object", Required: []string{"b", "a"}}
+	}
+	spec = copySpec(spec)
+	spec.Components.Parameters = map[string]*models.Parameter{"filter": {Name: "filter", In: "query", Schema: object()}}
+	spec.Components.Headers = map[string]*models.Header{"X-Meta": {Schema: object()}}
+	spec.Components.RequestBodies = map[string]*models.RequestBody{"Patch": {Content: map[string]*models.MediaType{"application/json": {Schema: object()}}}}
+	item := *spec.Paths["/items/{id}"]
+	item.Parameters = []*models.Parameter{{Name: "b", In: "query"}, {Name: "a", In: "query"}}
+	spec.Paths["/items/{id}"] = &item
+	canonical = Canonical(spec)
+	require.Equal(t, []string{"a", "b"}, canonical.Components.Parameters["filter"].Schema.Required)
+	require.Equal(t, []string{"a", "b"}, canonical.Components.Headers["X-Meta"].Schema.Required)
+	require.Equal(t, []string{"a", "b"}, canonical.Components.RequestBodies["Patch"].Content["application/json"].Schema.Required)
+	require.Equal(t, "a", canonical.Paths["/items/{id}"].Parameters[0].Name)
+	require.Equal(t, []string{"b", "a"}, spec.Components.Parameters["filter"].Schema.Required)
+	require.Equal(t, "b", spec.Paths["/items/{id}"].Parameters[0].Name)
+
+	b, err := doc.CanonicalJSON()
+	require.NoError(t, err)
+	require.Equal(t, byte('\n'), b[len(b)-1])
+	require.Contains(t, string(b), "\n  \"paths\": {\n")
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &v))
+}
+
+func TestCanonicalStable(t *testing.T) {
+	build := func() string {
+		doc := NewDocument("stable", "1.0.0")
+		doc.Register(&StableRouter{TestRouter{Method: "POST", Path: "/items"}})
+		b, err := doc.CanonicalYAML()
+		require.NoError(t, err)
+		return string(b)
+	}
+	want := build()
+	require.Contains(t, want, "$ref: '#/components/schemas/OpenapiItem'\n              example:\n                code: 0")
+	for i := 0; i < 20; i++ {
+		require.Equal(t, want, build())
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/Chise1/openapi/models"
 )
 
-var OPENAPI = models.OpenAPI{}
+// DefaultDocument Register2Openapi使用的默认文档
+var DefaultDocument = NewDocument("UniBGP", "0.1.0")
+
+// DefaultRes 路由没有声明任何响应时使用的200响应
 var DefaultRes = models.Response{
 	Description: "Successful Response",
 	Content: map[string]*models.MediaType{
@@ -13,14 +16,3 @@ var DefaultRes = models.Response{
 		},
 	},
 }
-
-func init() {
-	OPENAPI.Openapi = "3.0.2"
-	OPENAPI.Info = &models.Info{
-		Title:   "UniBGP",
-		Version: "0.1.0",
-	}
-	OPENAPI.Paths = map[string]*models.PathItem{}
-	OPENAPI.Components = &models.Components{}
-	OPENAPI.Components.Schemas = map[string]*models.Schema{}
-}
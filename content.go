@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Content 为同一个body或者响应声明多个媒体类型,每个值用于生成对应媒体类型的schema和example,
+// 例如openapi.Content{openapi.Json: User{}, "text/csv": ""}
+type Content map[ContentType]interface{}
+
+// sortedTypes 返回排序后的媒体类型,json排在最前面
+func (n Content) sortedTypes() []ContentType {
+	types := make([]ContentType, 0, len(n))
+	for t := range n {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if (types[i] == Json) != (types[j] == Json) {
+			return types[i] == Json
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// bodyContent 把body或者响应的值转换为Content,实现了IContentType的值使用自己的媒体类型,其他使用json
+func bodyContent(v interface{}) Content {
+	switch body := v.(type) {
+	case Content:
+		return body
+	case IContentType:
+		return Content{body.GetContentType(): v}
+	}
+	return Content{Json: v}
+}
+
+// Encoder 把v编码后写入w
+type Encoder func(w io.Writer, v interface{}) error
+
+// Decoder 从r中读取并解码到v
+type Decoder func(r io.Reader, v interface{}) error
+
+// codecs 按媒体类型注册的编码和解码函数
+type codecs struct {
+	mu       sync.RWMutex
+	encoders map[string]Encoder
+	decoders map[string]Decoder
+}
+
+func (n *codecs) encoder(contentType string) (Encoder, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	e, ok := n.encoders[contentType]
+	return e, ok
+}
+
+func (n *codecs) decoder(contentType string) (Decoder, bool) {
+	n.mu.RLock()
+	d, ok := n.decoders[contentType]
+	n.mu.RUnlock()
+	if ok {
+		return d, true
+	}
+	if isJSON(contentType) {
+		return func(r io.Reader, v interface{}) error {
+			return json.NewDecoder(r).Decode(v)
+		}, true
+	}
+	return nil, false
+}
+
+// SetEncoder 设置媒体类型的编码函数,例如text/csv.json默认使用encoding/json
+func (n *Router) SetEncoder(contentType string, encoder Encoder) {
+	n.codecs.mu.Lock()
+	defer n.codecs.mu.Unlock()
+	if n.codecs.encoders == nil {
+		n.codecs.encoders = map[string]Encoder{}
+	}
+	n.codecs.encoders[contentType] = encoder
+}
+
+// SetDecoder 设置媒体类型的解码函数.json默认使用encoding/json,
+// application/x-www-form-urlencoded按body的schema解码
+func (n *Router) SetDecoder(contentType string, decoder Decoder) {
+	n.codecs.mu.Lock()
+	defer n.codecs.mu.Unlock()
+	if n.codecs.decoders == nil {
+		n.codecs.decoders = map[string]Decoder{}
+	}
+	n.codecs.decoders[contentType] = decoder
+}
+
+// acceptRange Accept头中的一项
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept 解析Accept头,忽略不合法的项
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality 返回Accept中最具体的匹配项的q,没有匹配时返回-1
+func quality(ranges []acceptRange, contentType string) float64 {
+	q, specificity := -1.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.mediaType == contentType:
+			s = 2
+		case r.mediaType == "*/*":
+			s = 0
+		case strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(r.mediaType, "*")):
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// negotiate 按Accept头从offers中选择媒体类型,offers按优先顺序排列.
+// 没有Accept头时返回第一个,都不可接受时返回false
+func negotiate(header string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(header) == "" {
+		return offers[0], true
+	}
+	ranges := parseAccept(header)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := quality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ReportRow struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Report struct {
+	Rows []ReportRow `json:"rows"`
+}
+
+type ReportQuery struct {
+	Name string `json:"name"`
+}
+
+type ReportForm struct {
+	Name  string `json:"name"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type ContentRouter struct {
+	TestRouter
+}
+
+func (n *ContentRouter) GetReqBody() interface{} {
+	return Content{Json: ReportQuery{}, Form: ReportForm{}}
+}
+
+func (n *ContentRouter) GetResBody() map[int]interface{} {
+	return map[int]interface{}{200: Content{Json: Report{}, "text/csv": Report{}}}
+}
+
+func encodeCSV(w io.Writer, v interface{}) error {
+	cw := csv.NewWriter(w)
+	for _, row := range v.(*Report).Rows {
+		if err := cw.Write([]string{row.Name, strings.Repeat("*", row.Count)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func TestMultipleContentTypes(t *testing.T) {
+	router := NewRouter(NewDocument("reports", "1.0.0"), nil)
+	router.SetEncoder("text/csv", encodeCSV)
+	require.NoError(t, router.Handle(&ContentRouter{TestRouter{Method: "POST", Path: "/reports"}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			switch b := body.(type) {
+			case *ReportQuery:
+				return nil, &Report{Rows: []ReportRow{{Name: b.Name, Count: 1}}}
+			case *ReportForm:
+				return nil, &Report{Rows: []ReportRow{{Name: b.Name, Count: b.Limit}}}
+			}
+			return nil, NewProblem(http.StatusBadRequest, "no body")
+		}))
+	oper := router.Document().Snapshot().Paths["/reports"].Post
+	require.Len(t, oper.RequestBody.Content, 2)
+	require.Contains(t, oper.RequestBody.Content, "application/x-www-form-urlencoded")
+	require.Len(t, oper.Responses["200"].Content, 2)
+	require.Contains(t, oper.Responses["200"].Content, "text/csv")
+
+	for _, c := range []struct {
+		contentType, body, accept string
+		status                    int
+		resType, res              string
+	}{
+		{"application/json", `{"name":"a"}`, "", 200, "application/json", `{"rows":[{"name":"a","count":1}]}`},
+		{"application/json", `{"name":"a"}`, "text/csv", 200, "text/csv", "a,*\n"},
+		{"application/x-www-form-urlencoded", `name=b&limit=3`, "text/*;q=0.9, application/json;q=0.5", 200, "text/csv", "b,***\n"},
+		{"application/x-www-form-urlencoded", `name=b&limit=2`, "text/csv;q=0.1, */*", 200, "application/json", `{"rows":[{"name":"b","count":2}]}`},
+		{"application/json", `{"name":"a"}`, "application/xml", 406, "application/problem+json", ""},
+		{"text/plain", `a`, "", 415, "application/problem+json", ""},
+	} {
+		req := httptest.NewRequest("POST", "/reports", strings.NewReader(c.body))
+		req.Header.Set("Content-Type", c.contentType)
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, c.status, rec.Code, rec.Body.String())
+		require.Equal(t, c.resType, rec.Header().Get("Content-Type"))
+		if c.res != "" {
+			if c.resType == "application/json" {
+				require.JSONEq(t, c.res, rec.Body.String())
+			} else {
+				require.Equal(t, c.res, rec.Body.String())
+			}
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/csv"}
+	for accept, want := range map[string]string{
+		"":                                    "application/json",
+		"*/*":                                 "application/json",
+		"text/csv":                            "text/csv",
+		"text/*, application/json;q=0":        "text/csv",
+		"application/*;q=0.2, text/csv;q=0.3": "text/csv",
+		"image/png":                           "",
+		"text/*;q=0, */*":                     "application/json",
+	} {
+		got, ok := negotiate(accept, offers)
+		require.Equal(t, want, got, accept)
+		require.Equal(t, want != "", ok, accept)
+	}
+}
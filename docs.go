@@ -0,0 +1,206 @@
+package openapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// ui 中是swagger-ui v5.17.14的静态文件,来自swagger-ui的dist目录,没有修改.
+// 来源,校验和以及更新方法见ui/README.md,许可证见ui/LICENSE和ui/NOTICE
+//
+//go:embed ui/swagger-ui-bundle.js ui/swagger-ui.css ui/favicon-32x32.png
+var ui embed.FS
+
+var docsPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="swagger-ui.css">
+  <link rel="icon" type="image/png" href="favicon-32x32.png" sizes="32x32">
+  <style>body { margin: 0; background: #fafafa; }</style>
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="swagger-ui-bundle.js" charset="UTF-8"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "openapi.json",
+      dom_id: "#swagger-ui",
+      deepLinking: true,
+      presets: [SwaggerUIBundle.presets.apis],
+      layout: "BaseLayout"
+    });
+  </script>
+</body>
+</html>
+`))
+
+// HandlerOption 设置Handler的选项
+type HandlerOption func(h *docsHandler)
+
+// WithBasePath 设置Handler挂载的路径,例如"/docs",请求的路径去掉前缀后再处理
+func WithBasePath(basePath string) HandlerOption {
+	return func(h *docsHandler) {
+		h.basePath = strings.TrimSuffix(basePath, "/")
+	}
+}
+
+// WithDocsTitle 设置文档页面的标题,默认使用文档的info.title
+func WithDocsTitle(title string) HandlerOption {
+	return func(h *docsHandler) {
+		h.title = title
+	}
+}
+
+// Handler 返回提供文档的http.Handler,一行代码就可以为服务提供可以浏览的文档:
+// basePath/openapi.json和basePath/openapi.yaml返回文档,带有按内容计算的ETag,
+// basePath/返回可以离线使用的swagger-ui页面
+func (n *Document) Handler(opts ...HandlerOption) http.Handler {
+	h := &docsHandler{doc: n}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type docsHandler struct {
+	doc      *Document
+	basePath string
+	title    string
+
+	mu     sync.Mutex
+	spec   *models.OpenAPI //生成缓存时的快照
+	files  map[string]*docsFile
+	assets sync.Once
+	static map[string]*docsFile
+}
+
+// docsFile 缓存的响应内容
+type docsFile struct {
+	contentType string
+	body        []byte
+	etag        string
+}
+
+func newDocsFile(contentType string, body []byte) *docsFile {
+	sum := sha256.Sum256(body)
+	return &docsFile{contentType: contentType, body: body, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+func (n *docsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path
+	if n.basePath != "" {
+		if name != n.basePath && !strings.HasPrefix(name, n.basePath+"/") {
+			http.NotFound(w, r)
+			return
+		}
+		name = strings.TrimPrefix(name, n.basePath)
+	}
+	if name == "" {
+		http.Redirect(w, r, path.Base(n.basePath)+"/", http.StatusMovedPermanently)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var file *docsFile
+	var err error
+	switch name {
+	case "/", "/index.html":
+		file, err = n.file("index.html")
+	case "/openapi.json":
+		file, err = n.file("openapi.json")
+	case "/openapi.yaml":
+		file, err = n.file("openapi.yaml")
+	default:
+		file = n.asset(strings.TrimPrefix(name, "/"))
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if file == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", file.contentType)
+	w.Header().Set("ETag", file.etag)
+	// 文档会随注册变化,每次都按ETag重新验证
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(file.body))
+}
+
+// file 返回文档和页面,文档的快照变化时重新生成
+func (n *docsHandler) file(name string) (*docsFile, error) {
+	spec := n.doc.Snapshot()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.spec != spec {
+		files, err := n.render(spec)
+		if err != nil {
+			return nil, err
+		}
+		n.spec, n.files = spec, files
+	}
+	return n.files[name], nil
+}
+
+func (n *docsHandler) render(spec *models.OpenAPI) (map[string]*docsFile, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var y bytes.Buffer
+	if err := spec.WriteYAML(&y); err != nil {
+		return nil, err
+	}
+	title := n.title
+	if title == "" && spec.Info != nil {
+		title = spec.Info.Title
+	}
+	var page bytes.Buffer
+	if err := docsPage.Execute(&page, struct{ Title string }{title}); err != nil {
+		return nil, err
+	}
+	return map[string]*docsFile{
+		"openapi.json": newDocsFile("application/json", b),
+		"openapi.yaml": newDocsFile("application/yaml", y.Bytes()),
+		"index.html":   newDocsFile("text/html; charset=utf-8", page.Bytes()),
+	}, nil
+}
+
+// asset 返回嵌入的swagger-ui文件,不存在时返回nil
+func (n *docsHandler) asset(name string) *docsFile {
+	n.assets.Do(func() {
+		n.static = map[string]*docsFile{}
+		contentTypes := map[string]string{
+			".js":  "application/javascript",
+			".css": "text/css; charset=utf-8",
+			".png": "image/png",
+		}
+		entries, _ := fs.ReadDir(ui, "ui")
+		for _, entry := range entries {
+			b, err := ui.ReadFile("ui/" + entry.Name())
+			if err != nil {
+				continue
+			}
+			n.static[entry.Name()] = newDocsFile(contentTypes[path.Ext(entry.Name())], b)
+		}
+	})
+	return n.static[name]
+}
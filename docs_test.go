@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHandler(t *testing.T) {
+	doc := NewDocument("docs", "1.0.0")
+	doc.Register(&TestRouter{Method: "GET", Path: "/users", RepStruct: ReqStruct{}})
+	mux := http.NewServeMux()
+	mux.Handle("/docs/", doc.Handler(WithBasePath("/docs/"), WithDocsTitle("Docs <UI>")))
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/docs/openapi.json", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	want, err := json.Marshal(doc.Snapshot())
+	require.NoError(t, err)
+	require.JSONEq(t, string(want), rec.Body.String())
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = get("/docs/openapi.json", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.String())
+
+	doc.Register(&TestRouter{Method: "POST", Path: "/users", ReqStruct: ReqStruct{}})
+	rec = get("/docs/openapi.json", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	rec = get("/docs/openapi.yaml", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	var spec map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(rec.Body.Bytes(), &spec))
+	require.Contains(t, spec["paths"], "/users")
+
+	rec = get("/docs/", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<title>Docs &lt;UI&gt;</title>")
+	require.Contains(t, rec.Body.String(), `url: "openapi.json"`)
+
+	rec = get("/docs/swagger-ui-bundle.js", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/javascript", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "SwaggerUIBundle")
+
+	rec = get("/docs/missing.js", nil)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	req := httptest.NewRequest("POST", "/docs/openapi.json", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	doc.Handler(WithBasePath("/docs")).ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	require.Equal(t, http.StatusMovedPermanently, rec.Code)
+	require.Equal(t, "/docs/", rec.Header().Get("Location"))
+}
@@ -0,0 +1,303 @@
+package openapi
+
+import (
+	"fmt"
+	"github.com/Chise1/openapi/models"
+	"github.com/Chise1/openapi/validate"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Document 一个独立的openapi文档,拥有自己的paths和components,多个Document之间互不影响.
+// 注册路由和读取文档都可以并发调用.
+type Document struct {
+	Reflector *Reflector        //生成schema时使用的Reflector,为nil时使用默认配置,注册开始后不要修改
+	Conflict  ComponentConflict //不同的go类型生成同名component时的处理方式,默认自动改名
+	// OperationIDNamer 生成operationId,为nil时使用DefaultOperationID
+	OperationIDNamer OperationIDNamer
+
+	mu             sync.RWMutex
+	spec           *models.OpenAPI
+	snapshot       *models.OpenAPI //缓存的快照,注册后失效
+	componentTypes map[string]reflect.Type
+	componentNames map[reflect.Type]string
+	operationIDs   map[string]string           //operationId -> "METHOD path"
+	shared         map[string]*models.Response //状态码 -> 引用components.responses中共用响应的$ref
+	catchAll       map[string]bool             //由*rest注册的path模板,最后一个参数匹配剩余的整个路径
+
+	validatorMu    sync.Mutex
+	validatorSpec  *models.OpenAPI
+	validatorCache *validate.Validator
+}
+
+// NewDocument 创建一个新的文档,opts用于设置文档的其他元数据
+func NewDocument(title, version string, opts ...Option) *Document {
+	doc := &Document{
+		spec: &models.OpenAPI{
+			Openapi: "3.0.2",
+			Info: &models.Info{
+				Title:   title,
+				Version: version,
+			},
+			Paths: map[string]*models.PathItem{},
+			Components: &models.Components{
+				Schemas: map[string]*models.Schema{},
+			},
+		},
+		componentTypes: map[string]reflect.Type{},
+		componentNames: map[reflect.Type]string{},
+		operationIDs:   map[string]string{},
+		shared:         map[string]*models.Response{},
+		catchAll:       map[string]bool{},
+	}
+	doc.Apply(opts...)
+	return doc
+}
+
+// Register 把路由注册到文档中,出错时panic
+func (n *Document) Register(route RouteStruct) *RouterHelper {
+	schemas, err := n.RegisterE(route)
+	if err != nil {
+		panic(err)
+	}
+	return schemas
+}
+
+// RegisterE 把路由注册到文档中,出错时文档不会被修改.
+// 路由中有不支持的类型时返回*UnsupportedTypeError
+func (n *Document) RegisterE(route RouteStruct) (*RouterHelper, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	namer := newComponentNamer(n)
+	reflector := *n.reflector()
+	reflector.namer = namer
+	schemas, err := newRouterHelper(&reflector, route)
+	if err != nil {
+		return nil, err
+	}
+	if namer.err != nil {
+		return nil, namer.err
+	}
+	method := strings.ToUpper(route.GetMethod())
+	if method == "" {
+		method = http.MethodGet
+	}
+	template, err := parsePath(route.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPathParams(template, schemas.Parameters); err != nil {
+		return nil, fmt.Errorf("openapi: %s %s: %v", method, template.path, err)
+	}
+	path := template.path
+	if _, ok := n.spec.Paths[path]; ok && n.catchAll[path] != template.catchAll {
+		return nil, fmt.Errorf("openapi: %s %s: catch-all parameter does not match the path already registered", method, route.GetPath())
+	}
+	operationID, err := n.operationID(method, path, route)
+	if err != nil {
+		return nil, err
+	}
+	oper := &models.Operation{
+		Description: route.GetDescription(),
+		Summary:     "",
+		OperationId: operationID,
+		RequestBody: schemas.Body,
+		Parameters:  schemas.Parameters,
+	}
+	if r, ok := route.(RouteTagger); ok {
+		oper.Tags = r.GetTags()
+	}
+	if r, ok := route.(RouteSummarizer); ok {
+		oper.Summary = r.GetSummary()
+	}
+	if r, ok := route.(RouteDeprecator); ok {
+		oper.Deprecated = r.GetDeprecated()
+	}
+	if r, ok := route.(RouteSecurity); ok {
+		oper.Security = r.GetSecurity()
+		if err := checkSecurity(n.spec.Components, oper.Security); err != nil {
+			return nil, fmt.Errorf("openapi: %s %s: %v", method, path, err)
+		}
+	}
+	oper.Responses = schemas.Response
+	if len(oper.Responses) == 0 {
+		res := DefaultRes
+		oper.Responses = map[string]*models.Response{"200": &res}
+	}
+	n.addSharedResponses(oper)
+	// 快照里共享了PathItem,这里复制一份再修改
+	pathItem := &models.PathItem{}
+	if old, ok := n.spec.Paths[path]; ok {
+		*pathItem = *old
+	}
+	if old := pathItem.GetOperation(method); old != nil {
+		delete(n.operationIDs, old.OperationId)
+	}
+	if !pathItem.SetOperation(method, oper) {
+		return nil, fmt.Errorf("openapi: unsupported method %s for %s", method, path)
+	}
+	n.operationIDs[operationID] = method + " " + path
+	if template.catchAll {
+		n.catchAll[path] = true
+	}
+	n.addComponents(schemas.Components, namer)
+	n.spec.Paths[path] = pathItem
+	n.snapshot = nil
+	return schemas, nil
+}
+
+// Snapshot 返回文档当前状态的一致性快照,可以在注册继续进行时安全地序列化.
+// 快照和文档共享Operation和Schema等对象,调用方不能修改返回值.
+func (n *Document) Snapshot() *models.OpenAPI {
+	n.mu.RLock()
+	s := n.snapshot
+	n.mu.RUnlock()
+	if s != nil {
+		return s
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.snapshot == nil {
+		n.snapshot = copySpec(n.spec)
+	}
+	return n.snapshot
+}
+
+// copySpec 复制文档中注册时会修改的部分
+func copySpec(spec *models.OpenAPI) *models.OpenAPI {
+	s := *spec
+	if spec.Info != nil {
+		info := *spec.Info
+		s.Info = &info
+	}
+	s.Servers = append([]*models.Server(nil), spec.Servers...)
+	s.Tags = append([]*models.Tag(nil), spec.Tags...)
+	s.Security = append([]models.SecurityRequirement(nil), spec.Security...)
+	s.Paths = make(map[string]*models.PathItem, len(spec.Paths))
+	for path, item := range spec.Paths {
+		s.Paths[path] = item
+	}
+	if spec.Components != nil {
+		components := *spec.Components
+		components.Schemas = make(map[string]*models.Schema, len(spec.Components.Schemas))
+		for name, schema := range spec.Components.Schemas {
+			components.Schemas[name] = schema
+		}
+		if spec.Components.Responses != nil {
+			components.Responses = make(map[string]*models.Response, len(spec.Components.Responses))
+			for name, res := range spec.Components.Responses {
+				components.Responses[name] = res
+			}
+		}
+		s.Components = &components
+	}
+	return &s
+}
+
+func (n *Document) reflector() *Reflector {
+	if n.Reflector == nil {
+		return &Reflector{}
+	}
+	return n.Reflector
+}
+
+// SpecError 检查文档时发现的问题
+type SpecError []string
+
+func (e SpecError) Error() string {
+	return "openapi: invalid document: " + strings.Join(e, "; ")
+}
+
+// Validate 检查文档的一致性,有问题时返回SpecError
+func (n *Document) Validate() error {
+	spec := n.Snapshot()
+	var problems SpecError
+	owners := map[string]string{}
+	var links []linkTarget
+	for _, path := range sortedKeys(spec.Paths) {
+		for _, method := range models.Methods {
+			oper := spec.Paths[path].GetOperation(method)
+			if oper == nil {
+				continue
+			}
+			key := method + " " + path
+			if oper.OperationId == "" {
+				problems = append(problems, key+" has no operationId")
+			} else if owner, ok := owners[oper.OperationId]; ok {
+				problems = append(problems, fmt.Sprintf("operationId %q is used by both %s and %s", oper.OperationId, owner, key))
+			} else {
+				owners[oper.OperationId] = key
+			}
+			if err := checkSecurity(spec.Components, oper.Security); err != nil {
+				problems = append(problems, key+": "+err.Error())
+			}
+			for _, status := range sortedResponses(oper.Responses) {
+				if _, err := resolveResponse(spec.Components, oper.Responses[status]); err != nil {
+					problems = append(problems, fmt.Sprintf("%s response %s: %v", key, status, err))
+				}
+			}
+			links = append(links, linkTargets(key, oper)...)
+		}
+	}
+	for _, link := range links {
+		if _, ok := owners[link.operationID]; !ok {
+			problems = append(problems, fmt.Sprintf("%s links to unknown operationId %q", link.from, link.operationID))
+		}
+	}
+	if err := checkSecurity(spec.Components, spec.Security); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// linkTarget 响应中按operationId引用其他operation的link
+type linkTarget struct {
+	from        string //例如"GET /users response 201 link GetUser"
+	operationID string
+}
+
+// linkTargets 返回operation的响应中按operationId引用的operation
+func linkTargets(key string, oper *models.Operation) []linkTarget {
+	var targets []linkTarget
+	for _, status := range sortedResponses(oper.Responses) {
+		res := oper.Responses[status]
+		names := make([]string, 0, len(res.Links))
+		for name := range res.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if id := res.Links[name].OperationId; id != "" {
+				targets = append(targets, linkTarget{
+					from:        fmt.Sprintf("%s response %s link %s", key, status, name),
+					operationID: id,
+				})
+			}
+		}
+	}
+	return targets
+}
+
+func sortedResponses(m map[string]*models.Response) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
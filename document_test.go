@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentIsolation(t *testing.T) {
+	a := NewDocument("a", "1.0.0")
+	b := NewDocument("b", "2.0.0")
+	a.Register(&TestRouter{
+		Method:    "POST",
+		Path:      "/a",
+		ReqStruct: ReqStruct{Hello: "a"},
+	})
+	b.Register(&TestRouter{
+		Method:    "GET",
+		Path:      "/b/{name}",
+		Param:     ReqParam{Name: "b"},
+		ReqStruct: ReqStruct{Hello: "b"},
+	})
+
+	require.Contains(t, a.Snapshot().Paths, "/a")
+	require.NotContains(t, a.Snapshot().Paths, "/b/{name}")
+	require.Contains(t, b.Snapshot().Paths, "/b/{name}")
+	require.NotContains(t, b.Snapshot().Paths, "/a")
+	require.Contains(t, a.Snapshot().Components.Schemas, "ReqStruct")
+	require.Equal(t, "a", a.Snapshot().Info.Title)
+	require.Equal(t, "b", b.Snapshot().Info.Title)
+}
+
+func TestDocumentMergeMethods(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	for _, method := range []string{"get", "POST", "Patch", "head", "OPTIONS", "trace", "put", "delete"} {
+		doc.Register(&TestRouter{
+			Method:    method,
+			Path:      "/users",
+			ReqStruct: ReqStruct{},
+		})
+	}
+	item := doc.Snapshot().Paths["/users"]
+	require.NotNil(t, item)
+	for _, op := range []interface{}{item.Get, item.Post, item.Patch, item.Head, item.Options, item.Trace, item.Put, item.Delete} {
+		require.NotNil(t, op)
+	}
+	require.Panics(t, func() {
+		doc.Register(&TestRouter{Method: "CONNECT", Path: "/users", ReqStruct: ReqStruct{}})
+	})
+}
+
+func TestDocumentConcurrentRegister(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			doc.Register(&TestRouter{
+				Method:    "POST",
+				Path:      "/users/" + strconv.Itoa(i),
+				ReqStruct: ReqStruct{},
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := json.Marshal(doc.Snapshot())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Len(t, doc.Snapshot().Paths, 20)
+}
+
+func TestDocumentSnapshotIsStable(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&TestRouter{Method: "GET", Path: "/users", ReqStruct: ReqStruct{}})
+	snapshot := doc.Snapshot()
+	require.Same(t, snapshot, doc.Snapshot())
+
+	doc.Register(&TestRouter{Method: "POST", Path: "/users", ReqStruct: ReqStruct{}})
+	doc.Register(&TestRouter{Method: "GET", Path: "/orders", ReqStruct: ReqStruct{}})
+	require.Len(t, snapshot.Paths, 1)
+	require.Nil(t, snapshot.Paths["/users"].Post)
+	require.NotNil(t, doc.Snapshot().Paths["/users"].Post)
+}
+
+func TestDocumentRegisterUnsupportedType(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	_, err := doc.RegisterE(&TestRouter{Method: "POST", Path: "/orders", ReqStruct: UnsupportedOrder{}})
+	require.EqualError(t, err, "UnsupportedOrder.Items[].Price: unsupported type complex128")
+	require.Empty(t, doc.Snapshot().Paths)
+	require.Empty(t, doc.Snapshot().Components.Schemas)
+}
+
+type MetaRouter struct {
+	TestRouter
+	Tags        []string
+	Summary     string
+	OperationID string
+	Deprecated  bool
+}
+
+func (n *MetaRouter) GetTags() []string      { return n.Tags }
+func (n *MetaRouter) GetSummary() string     { return n.Summary }
+func (n *MetaRouter) GetOperationID() string { return n.OperationID }
+func (n *MetaRouter) GetDeprecated() bool    { return n.Deprecated }
+
+func TestDocumentOperationMetadata(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&MetaRouter{
+		TestRouter:  TestRouter{Method: "DELETE", Path: "/users/{name}", Param: PathName{}, ReqStruct: ReqStruct{}},
+		Tags:        []string{"users"},
+		Summary:     "Delete a user",
+		OperationID: "deleteUser",
+		Deprecated:  true,
+	})
+	oper := doc.Snapshot().Paths["/users/{name}"].Delete
+	require.Equal(t, []string{"users"}, oper.Tags)
+	require.Equal(t, "Delete a user", oper.Summary)
+	require.Equal(t, "deleteUser", oper.OperationId)
+	require.True(t, oper.Deprecated)
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/iancoleman/orderedmap"
+)
+
+// File multipart/form-data中上传的文件,schema为type: string, format: binary.
+// 也可以直接使用*multipart.FileHeader
+type File struct {
+	*multipart.FileHeader
+}
+
+var (
+	fileType       = reflect.TypeOf(File{})
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+)
+
+// maxFormMemory 解析multipart/form-data时保存在内存中的最大字节数,超出部分写入临时文件
+var maxFormMemory int64 = 32 << 20
+
+// isFileType 判断t是否为File或者multipart.FileHeader
+func isFileType(t reflect.Type) bool {
+	t = indirectType(t)
+	return t == fileType || t == fileHeaderType
+}
+
+// formField 表单body中的一个字段
+type formField struct {
+	name  string //表单中的名字,form tag优先,其次json tag
+	json  string //解码时使用的json名字
+	field reflect.StructField
+}
+
+// formFields 返回表单body的字段,嵌入的结构体展开,form或者json tag为"-"的字段忽略
+func formFields(t reflect.Type) []formField {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []formField
+	for _, f := range reflect.VisibleFields(t) {
+		jsonName, jsonExist := tagName(f.Tag, "json")
+		if f.PkgPath != "" || jsonName == "-" {
+			continue
+		}
+		if f.Anonymous && !jsonExist && indirectType(f.Type).Kind() == reflect.Struct {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		name, _ := tagName(f.Tag, "form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = jsonName
+		}
+		fields = append(fields, formField{name: name, json: jsonName, field: f})
+	}
+	return fields
+}
+
+// tagName 返回tag中逗号前的名字
+func tagName(tag reflect.StructTag, key string) (string, bool) {
+	v, ok := tag.Lookup(key)
+	return strings.Split(v, ",")[0], ok
+}
+
+// formBody 生成表单body的媒体类型,结构体直接展开不引用component,属性使用表单中的名字.
+// multipart/form-data的字段按contentType和formHeader tag生成encoding,
+// 例如`form:"avatar" contentType:"image/png" formHeader:"X-Checksum"`
+func (n *RouterHelper) formBody(reflector *Reflector, v interface{}, contentType ContentType) *models.MediaType {
+	t := indirectType(reflect.TypeOf(v))
+	if t.Kind() != reflect.Struct {
+		return &models.MediaType{Schema: n.schema(reflector, t)}
+	}
+	components := Definitions{}
+	st := &models.Schema{
+		Type:                 "object",
+		Properties:           orderedmap.New(),
+		AdditionalProperties: []byte("false"),
+	}
+	reflector.reflectStructFields(st, components, t)
+	n.updateComponents(components)
+
+	fields := map[string]formField{}
+	for _, f := range formFields(t) {
+		fields[f.field.Name] = f
+	}
+	names := map[string]string{} //属性名 -> 表单中的名字
+	properties := orderedmap.New()
+	media := &models.MediaType{Schema: st}
+	for _, key := range st.Properties.Keys() {
+		p, _ := st.Properties.Get(key)
+		property := p.(*models.Schema)
+		f, ok := fields[propertyField(property)]
+		if !ok {
+			continue
+		}
+		names[key] = f.name
+		properties.Set(f.name, property)
+		if contentType != FormData {
+			continue
+		}
+		if encoding := partEncoding(f.field); encoding != nil {
+			if media.Encoding == nil {
+				media.Encoding = map[string]*models.Encoding{}
+			}
+			media.Encoding[f.name] = encoding
+		}
+	}
+	st.Properties = properties
+	required := st.Required[:0]
+	for _, key := range st.Required {
+		if name, ok := names[key]; ok {
+			required = append(required, name)
+		}
+	}
+	st.Required = required
+	return media
+}
+
+func (n *RouterHelper) schema(reflector *Reflector, t reflect.Type) *models.Schema {
+	components := Definitions{}
+	schema := reflector.reflectTypeToSchema(components, t)
+	n.updateComponents(components)
+	return schema
+}
+
+// propertyField 返回属性对应的go字段名,nullable的属性包在oneOf中
+func propertyField(property *models.Schema) string {
+	if property.FieldName == "" && len(property.OneOf) > 0 {
+		return property.OneOf[0].FieldName
+	}
+	return property.FieldName
+}
+
+// partEncoding 按字段的contentType和formHeader tag生成multipart中一部分的encoding,都没有时返回nil
+func partEncoding(f reflect.StructField) *models.Encoding {
+	contentType := f.Tag.Get("contentType")
+	headers := f.Tag.Get("formHeader")
+	if contentType == "" && headers == "" {
+		return nil
+	}
+	encoding := &models.Encoding{ContentType: contentType}
+	for _, name := range strings.Split(headers, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if encoding.Headers == nil {
+			encoding.Headers = map[string]*models.Header{}
+		}
+		encoding.Headers[name] = &models.Header{Schema: &models.Schema{Type: "string"}}
+	}
+	return encoding
+}
+
+// setFiles 把multipart中上传的文件设置到v中类型为File,*multipart.FileHeader或者它们的切片的字段
+func setFiles(v reflect.Value, fields []formField, files map[string][]*multipart.FileHeader) {
+	v = reflect.Indirect(v)
+	for _, f := range fields {
+		headers := files[f.name]
+		t := f.field.Type
+		if t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if len(headers) == 0 || !isFileType(t) {
+			continue
+		}
+		fv := v.FieldByIndex(f.field.Index)
+		if fv.Kind() != reflect.Slice {
+			fv.Set(fileValue(fv.Type(), headers[0]))
+			continue
+		}
+		s := reflect.MakeSlice(fv.Type(), 0, len(headers))
+		for _, h := range headers {
+			s = reflect.Append(s, fileValue(fv.Type().Elem(), h))
+		}
+		fv.Set(s)
+	}
+}
+
+// fileValue 把上传的文件转换为t类型的值
+func fileValue(t reflect.Type, h *multipart.FileHeader) reflect.Value {
+	if indirectType(t) == fileHeaderType {
+		if t.Kind() == reflect.Ptr {
+			return reflect.ValueOf(h)
+		}
+		return reflect.ValueOf(h).Elem()
+	}
+	v := reflect.ValueOf(File{FileHeader: h})
+	if t.Kind() == reflect.Ptr {
+		p := reflect.New(fileType)
+		p.Elem().Set(v)
+		return p
+	}
+	return v
+}
@@ -0,0 +1,209 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type UploadBody struct {
+	Name        string                  `json:"name" form:"user_name"`
+	Avatar      File                    `form:"avatar" contentType:"image/png" formHeader:"X-Checksum"`
+	Attachments []*multipart.FileHeader `json:"attachments,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Secret      string                  `json:"secret" form:"-"`
+}
+
+func (UploadBody) GetContentType() ContentType {
+	return FormData
+}
+
+type UploadRouter struct {
+	TestRouter
+}
+
+func (n *UploadRouter) GetReqBody() interface{} {
+	return UploadBody{}
+}
+
+type LoginBody struct {
+	User string `json:"user" form:"username"`
+	Keep bool   `json:"keep,omitempty"`
+}
+
+func TestFormBody(t *testing.T) {
+	doc := NewDocument("upload", "1.0.0")
+	doc.Register(&UploadRouter{TestRouter{Method: "POST", Path: "/upload"}})
+	doc.Register(&TestRouter{Method: "POST", Path: "/login", ReqStruct: Content{Form: LoginBody{}}})
+	spec := doc.Snapshot()
+
+	b, err := json.Marshal(spec.Paths["/upload"].Post.RequestBody.Content)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"multipart/form-data": {
+		"schema": {
+			"type": "object",
+			"additionalProperties": false,
+			"required": ["user_name", "avatar"],
+			"properties": {
+				"user_name": {"type": "string", "title": "Name"},
+				"avatar": {"type": "string", "format": "binary", "title": "Avatar"},
+				"attachments": {"type": "array", "items": {"type": "string", "format": "binary"}, "title": "Attachments"},
+				"tags": {"type": "array", "items": {"type": "string"}, "title": "Tags"}
+			}
+		},
+		"encoding": {
+			"avatar": {"contentType": "image/png", "headers": {"X-Checksum": {"schema": {"type": "string"}}}}
+		}
+	}}`, string(b))
+	require.NotContains(t, spec.Components.Schemas, "UploadBody")
+
+	b, err = json.Marshal(spec.Paths["/login"].Post.RequestBody.Content)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"application/x-www-form-urlencoded": {
+		"schema": {
+			"type": "object",
+			"additionalProperties": false,
+			"required": ["username"],
+			"properties": {
+				"username": {"type": "string", "title": "User"},
+				"keep": {"type": "boolean", "title": "Keep"}
+			}
+		}
+	}}`, string(b))
+}
+
+func TestFormUpload(t *testing.T) {
+	router := NewRouter(NewDocument("upload", "1.0.0"), nil)
+	require.NoError(t, router.Handle(&UploadRouter{TestRouter{Method: "POST", Path: "/upload"}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			b := body.(*UploadBody)
+			f, err := b.Avatar.Open()
+			require.NoError(t, err)
+			defer f.Close()
+			avatar, err := ioutil.ReadAll(f)
+			require.NoError(t, err)
+			names := []string{b.Name, b.Avatar.Filename, string(avatar), strings.Join(b.Tags, "+"), b.Secret}
+			for _, a := range b.Attachments {
+				names = append(names, a.Filename)
+			}
+			return nil, ReqStruct{Hello: strings.Join(names, ",")}
+		}))
+	require.NoError(t, router.Handle(&TestRouter{Method: "POST", Path: "/login", ReqStruct: Content{Form: LoginBody{}}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			b := body.(*LoginBody)
+			return nil, ReqStruct{Hello: b.User + ":" + strconv.FormatBool(b.Keep)}
+		}))
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("user_name", "bob"))
+	require.NoError(t, w.WriteField("tags", "a"))
+	require.NoError(t, w.WriteField("tags", "b"))
+	require.NoError(t, w.WriteField("secret", "s"))
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="avatar"; filename="me.png"`)
+	h.Set("Content-Type", "image/png")
+	part, err := w.CreatePart(h)
+	require.NoError(t, err)
+	_, _ = part.Write([]byte("png"))
+	for _, name := range []string{"a.txt", "b.txt"} {
+		part, err := w.CreateFormFile("attachments", name)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(name))
+	}
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/upload", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"bob,me.png,png,a+b,,a.txt,b.txt"}`, rec.Body.String())
+
+	req = httptest.NewRequest("POST", "/upload", strings.NewReader("--x\r\nbroken"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	req = httptest.NewRequest("POST", "/login", strings.NewReader("username=alice&keep=true"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"alice:true"}`, rec.Body.String())
+}
+
+func TestFormValidation(t *testing.T) {
+	router := NewRouter(NewDocument("upload", "1.0.0"), nil)
+	require.NoError(t, router.Handle(&TestRouter{Method: "POST", Path: "/login", ReqStruct: Content{Form: LoginBody{}}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			return nil, ReqStruct{Hello: body.(*LoginBody).User}
+		}))
+	req := httptest.NewRequest("POST", "/login", strings.NewReader("keep=true&other=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, []InvalidParam{{Name: "/username", In: "body", Reason: "is required"}}, problem.InvalidParams)
+}
+
+func TestFormUploadTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	old := maxFormMemory
+	maxFormMemory = 0
+	defer func() { maxFormMemory = old }()
+
+	router := NewRouter(NewDocument("upload", "1.0.0"), nil, WithMaxBodySize(1024))
+	require.NoError(t, router.Handle(&UploadRouter{TestRouter{Method: "POST", Path: "/upload"}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			entries, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			require.NotEmpty(t, entries)
+			return nil, ReqStruct{Hello: body.(*UploadBody).Avatar.Filename}
+		}))
+	// 外层的中间件替换了请求,http.Server不会删除这个请求的临时文件
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r.WithContext(context.Background()))
+	})
+	upload := func(size int) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		require.NoError(t, w.WriteField("user_name", "bob"))
+		part, err := w.CreateFormFile("avatar", "me.png")
+		require.NoError(t, err)
+		_, _ = part.Write(bytes.Repeat([]byte("p"), size))
+		require.NoError(t, w.Close())
+		req := httptest.NewRequest("POST", "/upload", &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := upload(100)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"me.png"}`, rec.Body.String())
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	rec = upload(2048)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, rec.Body.String())
+	entries, err = ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
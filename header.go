@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// headerTag 解析header tag,返回响应头的名字和是否必须,例如`header:"Location,required"`
+func headerTag(tag string) (string, bool) {
+	parts := strings.Split(tag, ",")
+	required := false
+	for _, opt := range parts[1:] {
+		required = required || opt == "required"
+	}
+	return parts[0], required
+}
+
+// response 返回状态码对应的响应,没有时创建一个没有内容的响应
+func (n *RouterHelper) response(status int) *models.Response {
+	key := statusKey(status)
+	res, ok := n.Response[key]
+	if !ok {
+		res = &models.Response{Description: statusDescription(status)}
+		n.Response[key] = res
+	}
+	return res
+}
+
+// resHeaders 把GetResPara和RouteResponseHeaders声明的响应头加到响应中
+func (n *RouterHelper) resHeaders(reflector *Reflector, route RouteStruct) error {
+	var common map[string]*models.Header
+	if v := route.GetResPara(); v != nil {
+		headers, err := n.reflectHeaders(reflector, v)
+		if err != nil {
+			return err
+		}
+		common = headers
+	}
+	for _, res := range n.Response {
+		for name, header := range common {
+			setHeader(res, name, header)
+		}
+	}
+	r, ok := route.(RouteResponseHeaders)
+	if !ok {
+		return nil
+	}
+	resHeaders := r.GetResHeaders()
+	for _, status := range sortedStatuses(resHeaders) {
+		v := resHeaders[status]
+		if v == nil {
+			continue
+		}
+		headers, err := n.reflectHeaders(reflector, v)
+		if err != nil {
+			return err
+		}
+		res := n.response(status)
+		for name, header := range common {
+			setHeader(res, name, header)
+		}
+		for name, header := range headers {
+			setHeader(res, name, header)
+		}
+	}
+	return nil
+}
+
+func setHeader(res *models.Response, name string, header *models.Header) {
+	if res.Headers == nil {
+		res.Headers = map[string]*models.Header{}
+	}
+	res.Headers[name] = header
+}
+
+// reflectHeaders 把结构体中带header tag的字段转换为响应头,字段的openapi tag用于设置schema
+func (n *RouterHelper) reflectHeaders(reflector *Reflector, v interface{}) (map[string]*models.Header, error) {
+	t := indirectType(reflect.TypeOf(v))
+	headers := map[string]*models.Header{}
+	err := catchUnsupported(t, func() {
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		components := Definitions{}
+		parent := &models.Schema{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, required := headerTag(f.Tag.Get("header"))
+			if f.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			schema := reflector.reflectElem(components, f.Type, "."+f.Name)
+			schema.StructKeywordsFromTags(f, parent, name)
+			headers[name] = &models.Header{
+				Description: schema.Description,
+				Required:    required,
+				Schema:      schema,
+			}
+		}
+		n.updateComponents(components)
+	})
+	return headers, err
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type RateLimitHeaders struct {
+	Remaining int `header:"X-RateLimit-Remaining,required" openapi:"gte=0" openapi_desc:"剩余的请求次数"`
+	internal  string
+}
+
+type CreatedHeaders struct {
+	Location string `header:"Location,required"`
+	ETag     string `header:"ETag"`
+}
+
+type CreateUserRouter struct {
+	TestRouter
+	Links map[int]map[string]*models.Link
+}
+
+func (n *CreateUserRouter) GetResHeaders() map[int]interface{} {
+	return map[int]interface{}{201: CreatedHeaders{}}
+}
+
+func (n *CreateUserRouter) GetLinks() map[int]map[string]*models.Link {
+	return n.Links
+}
+
+func TestResponseHeadersAndLinks(t *testing.T) {
+	doc := NewDocument("users", "1.0.0")
+	route := &CreateUserRouter{
+		TestRouter: TestRouter{Method: "POST", Path: "/users", ReqStruct: ReqStruct{}, ResParam: RateLimitHeaders{}},
+		Links: map[int]map[string]*models.Link{
+			201: {"GetUser": {OperationId: "getUser", Parameters: map[string]interface{}{"id": "$response.header.Location"}}},
+			202: {"GetUser": {OperationId: "getUser", Parameters: map[string]interface{}{"id": "$request.body#/hello"}}},
+		},
+	}
+	doc.Register(route)
+	responses := doc.Snapshot().Paths["/users"].Post.Responses
+
+	b, err := json.Marshal(responses["200"].Headers)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"X-RateLimit-Remaining":{"description":"剩余的请求次数","required":true,"schema":{"type":"integer","maximum":2147483647,"minimum":0,"title":"Remaining","description":"剩余的请求次数"}}}`, string(b))
+	require.Len(t, responses["201"].Headers, 3)
+	require.True(t, responses["201"].Headers["Location"].Required)
+	require.False(t, responses["201"].Headers["ETag"].Required)
+	require.Equal(t, "$response.header.Location", responses["201"].Links["GetUser"].Parameters["id"])
+	// 只在links中声明的状态码也有共用的响应头
+	require.Equal(t, responses["200"].Headers, responses["202"].Headers)
+
+	require.EqualError(t, doc.Validate(), `openapi: invalid document: POST /users response 201 link GetUser links to unknown operationId "getUser"; `+
+		`POST /users response 202 link GetUser links to unknown operationId "getUser"`)
+	doc.Register(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/users/{id}", Param: PathID{}}, OperationID: "getUser"})
+	require.NoError(t, doc.Validate())
+}
+
+type DescribedRouter struct {
+	TestRouter
+}
+
+func (n *DescribedRouter) GetResDescriptions() map[int]string {
+	return map[int]string{http.StatusAccepted: "已接受"}
+}
+
+func TestDescribedResponseHeaders(t *testing.T) {
+	doc := NewDocument("users", "1.0.0")
+	doc.Register(&DescribedRouter{TestRouter{Method: "POST", Path: "/users", ResParam: RateLimitHeaders{}}})
+	res := doc.Snapshot().Paths["/users"].Post.Responses["202"]
+	require.Equal(t, "已接受", res.Description)
+	require.Contains(t, res.Headers, "X-RateLimit-Remaining")
+}
+
+func TestRouterWritesResponseHeaders(t *testing.T) {
+	router := NewRouter(NewDocument("users", "1.0.0"), nil)
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/limits", ResParam: RateLimitHeaders{}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			return RateLimitHeaders{Remaining: 9, internal: "x"}, ReqStruct{Hello: "world"}
+		}))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/limits", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "9", rec.Header().Get("X-RateLimit-Remaining"))
+	require.NoError(t, router.Document().ValidateResponse(httptest.NewRequest("GET", "/limits", nil), rec.Code, rec.Header(), rec.Body.Bytes()))
+}
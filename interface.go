@@ -1,5 +1,7 @@
 package openapi
 
+import "github.com/Chise1/openapi/models"
+
 type ContentType string
 
 const (
@@ -27,6 +29,48 @@ type RouteStruct interface {
 	GetMethod() string
 }
 
+// RouteTagger 路由可选实现,返回operation的tags,用于文档分组
+type RouteTagger interface {
+	GetTags() []string
+}
+
+// RouteSummarizer 路由可选实现,返回operation的summary
+type RouteSummarizer interface {
+	GetSummary() string
+}
+
+// RouteOperationID 路由可选实现,返回固定的operationId,生成客户端时作为方法名
+type RouteOperationID interface {
+	GetOperationID() string
+}
+
+// RouteDeprecator 路由可选实现,返回true时operation标记为deprecated
+type RouteDeprecator interface {
+	GetDeprecated() bool
+}
+
+// RouteSecurity 路由可选实现,返回operation的安全需求,引用的方案需要先通过Document.AddSecurityScheme注册
+type RouteSecurity interface {
+	GetSecurity() []models.SecurityRequirement
+}
+
+// RouteResponseHeaders 路由可选实现,按状态码声明响应头的结构体,字段使用header tag,
+// 例如`header:"Location,required"`.GetResPara返回的结构体声明所有响应共有的响应头
+type RouteResponseHeaders interface {
+	GetResHeaders() map[int]interface{}
+}
+
+// RouteLinks 路由可选实现,按状态码声明响应的links,key为link的名字,
+// 例如{201: {"GetUser": {OperationId: "getUser", Parameters: {"id": "$response.body#/id"}}}}
+type RouteLinks interface {
+	GetLinks() map[int]map[string]*models.Link
+}
+
+// RouteResponseDescriptions 路由可选实现,按状态码覆盖响应的描述,默认使用http.StatusText
+type RouteResponseDescriptions interface {
+	GetResDescriptions() map[int]string
+}
+
 // IBody body返回的数据结构
 type IBody interface {
 	Marshal() ([]byte, error)
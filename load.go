@@ -0,0 +1,364 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Load 解析json或者yaml格式的openapi 3.0.x文档.
+// schema的properties保持原来的顺序,x-开头的扩展字段保存在各个对象的Extensions中,安全方案会被检查
+func Load(r io.Reader) (*models.OpenAPI, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '{' {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("openapi: load: %v", err)
+		}
+		if len(doc.Content) == 0 {
+			return nil, fmt.Errorf("openapi: load: empty document")
+		}
+		var buf bytes.Buffer
+		if err := yamlToJSON(&buf, doc.Content[0]); err != nil {
+			return nil, fmt.Errorf("openapi: load: %v", err)
+		}
+		b = buf.Bytes()
+	}
+	spec := &models.OpenAPI{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("openapi: load: %v", err)
+	}
+	if !strings.HasPrefix(spec.Openapi, "3.0.") {
+		return nil, fmt.Errorf("openapi: load: unsupported version %q, want 3.0.x", spec.Openapi)
+	}
+	if spec.Paths == nil {
+		spec.Paths = map[string]*models.PathItem{}
+	}
+	if spec.Components == nil {
+		spec.Components = &models.Components{}
+	}
+	names := make([]string, 0, len(spec.Components.SecuritySchemes))
+	for name := range spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		scheme := spec.Components.SecuritySchemes[name]
+		if scheme == nil {
+			return nil, fmt.Errorf("openapi: load: security scheme %q is empty", name)
+		}
+		if err := scheme.Validate(); err != nil {
+			return nil, fmt.Errorf("openapi: load: security scheme %q: %v", name, err)
+		}
+		if err := checkSchemeVersion(spec.Openapi, scheme); err != nil {
+			return nil, fmt.Errorf("openapi: load: security scheme %q: %v", name, err)
+		}
+	}
+	return spec, nil
+}
+
+// yamlToJSON 把yaml节点写为json,对象的key保持原来的顺序
+func yamlToJSON(w *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			w.WriteString("null")
+			return nil
+		}
+		return yamlToJSON(w, node.Content[0])
+	case yaml.AliasNode:
+		return yamlToJSON(w, node.Alias)
+	case yaml.MappingNode:
+		w.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			w.Write(key)
+			w.WriteByte(':')
+			if err := yamlToJSON(w, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('}')
+	case yaml.SequenceNode:
+		w.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := yamlToJSON(w, item); err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+	case yaml.ScalarNode:
+		var v interface{}
+		switch node.ShortTag() {
+		case "!!null", "!!bool", "!!int", "!!float":
+			if err := node.Decode(&v); err != nil {
+				return err
+			}
+		default:
+			// 字符串,日期等都按字符串处理
+			v = node.Value
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", node.Line, err)
+		}
+		w.Write(b)
+	}
+	return nil
+}
+
+// Merge 把spec中的paths,components,tags,servers,security,externalDocs和扩展字段合并到文档中,用于合并手写的文档和生成的文档.
+// 已经存在的path中summary,description,参数,servers和扩展字段也会合并.
+// 已经注册的operation,重复的operationId,两边都设置了但不同的security和externalDocs,
+// 以及同名但内容不同的component,参数或者字段都会返回错误,出错时文档不会被修改
+func (n *Document) Merge(spec *models.OpenAPI) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	s := copySpec(n.spec)
+	ids := map[string]string{}
+	for _, path := range sortedKeys(spec.Paths) {
+		item := spec.Paths[path]
+		if item == nil {
+			continue
+		}
+		pathItem := &models.PathItem{}
+		if old, ok := s.Paths[path]; ok {
+			*pathItem = *old
+			if err := mergePathItem(path, pathItem, item); err != nil {
+				return err
+			}
+		} else {
+			*pathItem = *item
+		}
+		for _, method := range models.Methods {
+			oper := item.GetOperation(method)
+			if oper == nil {
+				continue
+			}
+			if old := s.Paths[path]; old != nil && old.GetOperation(method) != nil {
+				return fmt.Errorf("openapi: merge: %s %s is already registered", method, path)
+			}
+			if id := oper.OperationId; id != "" {
+				owner, ok := n.operationIDs[id]
+				if !ok {
+					owner, ok = ids[id]
+				}
+				if ok {
+					return fmt.Errorf("openapi: merge: operationId %q of %s %s is already used by %s", id, method, path, owner)
+				}
+				ids[id] = method + " " + path
+			}
+			pathItem.SetOperation(method, oper)
+		}
+		s.Paths[path] = pathItem
+	}
+	if c := spec.Components; c != nil {
+		components := *s.Components
+		var err error
+		if components.Schemas, err = mergeComponents("schemas", components.Schemas, c.Schemas); err != nil {
+			return err
+		}
+		if components.Responses, err = mergeComponents("responses", components.Responses, c.Responses); err != nil {
+			return err
+		}
+		if components.Parameters, err = mergeComponents("parameters", components.Parameters, c.Parameters); err != nil {
+			return err
+		}
+		if components.Examples, err = mergeComponents("examples", components.Examples, c.Examples); err != nil {
+			return err
+		}
+		if components.RequestBodies, err = mergeComponents("requestBodies", components.RequestBodies, c.RequestBodies); err != nil {
+			return err
+		}
+		if components.Headers, err = mergeComponents("headers", components.Headers, c.Headers); err != nil {
+			return err
+		}
+		if components.SecuritySchemes, err = mergeComponents("securitySchemes", components.SecuritySchemes, c.SecuritySchemes); err != nil {
+			return err
+		}
+		if components.Links, err = mergeComponents("links", components.Links, c.Links); err != nil {
+			return err
+		}
+		if components.Callbacks, err = mergeComponents("callbacks", components.Callbacks, c.Callbacks); err != nil {
+			return err
+		}
+		if components.PathItems, err = mergeComponents("pathItems", components.PathItems, c.PathItems); err != nil {
+			return err
+		}
+		s.Components = &components
+	}
+	if len(spec.Security) > 0 {
+		if len(s.Security) > 0 && !sameJSON(s.Security, spec.Security) {
+			return fmt.Errorf("openapi: merge: security conflicts with the existing one")
+		}
+		if err := checkSecurity(s.Components, spec.Security); err != nil {
+			return fmt.Errorf("openapi: merge: security: %v", err)
+		}
+		s.Security = spec.Security
+	}
+	if spec.ExternalDocs != nil {
+		if s.ExternalDocs != nil && !sameJSON(s.ExternalDocs, spec.ExternalDocs) {
+			return fmt.Errorf("openapi: merge: externalDocs conflicts with the existing one")
+		}
+		s.ExternalDocs = spec.ExternalDocs
+	}
+	extensions, err := mergeExtensions("", s.Extensions, spec.Extensions)
+	if err != nil {
+		return err
+	}
+	s.Extensions = extensions
+	for _, tag := range spec.Tags {
+		if !hasTag(s.Tags, tag.Name) {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	for _, server := range spec.Servers {
+		if !hasServer(s.Servers, server.Url) {
+			s.Servers = append(s.Servers, server)
+		}
+	}
+	for id, owner := range ids {
+		n.operationIDs[id] = owner
+	}
+	n.spec = s
+	n.snapshot = nil
+	return nil
+}
+
+// mergePathItem 合并已经存在的path中operation以外的字段,dst是可以修改的副本.
+// 两边都设置了但是不同的summary,description,$ref,同名参数和扩展字段返回错误
+func mergePathItem(path string, dst, src *models.PathItem) error {
+	for _, field := range []struct {
+		name     string
+		dst, src *string
+	}{
+		{"$ref", &dst.Ref, &src.Ref},
+		{"summary", &dst.Summary, &src.Summary},
+		{"description", &dst.Description, &src.Description},
+	} {
+		if *field.src == "" || *field.src == *field.dst {
+			continue
+		}
+		if *field.dst != "" {
+			return fmt.Errorf("openapi: merge: %s of %s conflicts with the existing one", field.name, path)
+		}
+		*field.dst = *field.src
+	}
+	params := dst.Parameters
+	for _, param := range src.Parameters {
+		exists := false
+		for _, old := range dst.Parameters {
+			if old.In == param.In && old.Name == param.Name {
+				if !sameJSON(old, param) {
+					return fmt.Errorf("openapi: merge: parameter %s in %s of %s conflicts with the existing one", param.Name, param.In, path)
+				}
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			params = append(params[:len(params):len(params)], param)
+		}
+	}
+	dst.Parameters = params
+	for _, server := range src.Servers {
+		if !hasServer(dst.Servers, server.Url) {
+			dst.Servers = append(dst.Servers[:len(dst.Servers):len(dst.Servers)], server)
+		}
+	}
+	extensions, err := mergeExtensions(" of "+path, dst.Extensions, src.Extensions)
+	if err != nil {
+		return err
+	}
+	dst.Extensions = extensions
+	return nil
+}
+
+// mergeExtensions 返回合并后的扩展字段,同名但值不同时返回错误,where用于错误信息
+func mergeExtensions(where string, dst, src map[string]interface{}) (map[string]interface{}, error) {
+	if len(src) == 0 {
+		return dst, nil
+	}
+	extensions := make(map[string]interface{}, len(dst)+len(src))
+	for key, value := range dst {
+		extensions[key] = value
+	}
+	for _, key := range sortedKeys(src) {
+		if old, ok := extensions[key]; ok && !sameJSON(old, src[key]) {
+			return nil, fmt.Errorf("openapi: merge: %s%s conflicts with the existing one", key, where)
+		}
+		extensions[key] = src[key]
+	}
+	return extensions, nil
+}
+
+// mergeComponents 返回合并后的新map,同名且序列化结果相同的component保留原来的
+func mergeComponents[V any](kind string, dst, src map[string]V) (map[string]V, error) {
+	if len(src) == 0 {
+		return dst, nil
+	}
+	merged := make(map[string]V, len(dst)+len(src))
+	for name, v := range dst {
+		merged[name] = v
+	}
+	for _, name := range sortedKeys(src) {
+		if old, ok := merged[name]; ok {
+			if !sameJSON(old, src[name]) {
+				return nil, fmt.Errorf("openapi: merge: components.%s.%s conflicts with the existing one", kind, name)
+			}
+			continue
+		}
+		merged[name] = src[name]
+	}
+	return merged, nil
+}
+
+func sameJSON(a, b interface{}) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(x, y)
+}
+
+func hasTag(tags []*models.Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasServer(servers []*models.Server, url string) bool {
+	for _, server := range servers {
+		if server.Url == url {
+			return true
+		}
+	}
+	return false
+}
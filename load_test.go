@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	var docs []*models.OpenAPI
+	for _, path := range []string{"fixtures/petstore.yaml", "fixtures/petstore.json"} {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		spec, err := Load(f)
+		f.Close()
+		require.NoError(t, err, path)
+
+		require.Equal(t, "Petstore", spec.Info.Title)
+		require.Equal(t, "https://example.com/logo.png", spec.Info.Extensions["x-logo"])
+		item := spec.Paths["/pets/{id}"]
+		require.Len(t, item.Parameters, 1)
+		require.Equal(t, "path", item.Parameters[0].In)
+		oper := item.Get
+		require.Equal(t, "getPet", oper.OperationId)
+		require.Equal(t, float64(100), oper.Extensions["x-rate-limit"])
+		require.Equal(t, "A pet.\nWith a multi-line description.\n", oper.Responses["200"].Description)
+
+		pet := spec.Components.Schemas["Pet"]
+		require.Equal(t, []string{"name", "id", "age", "tags"}, pet.Properties.Keys())
+		age, _ := pet.Properties.Get("age")
+		require.Equal(t, "number", age.(*models.Schema).Type)
+		require.Equal(t, 1.5, age.(*models.Schema).Example)
+		require.Equal(t, "store.Pet", pet.Extras["x-go-type"])
+
+		schemes := spec.Components.SecuritySchemes
+		require.Equal(t, models.ApiKey, schemes["apiKey"].Type)
+		require.Equal(t, "X-API-Key", schemes["apiKey"].Name)
+		require.Equal(t, "Bearer", schemes["bearer"].Scheme)
+		require.Equal(t, "JWT", schemes["bearer"].BearerFormat)
+		require.True(t, schemes["oauth"].HasScope("read"))
+
+		// 所有支持扩展字段的对象都保留x-开头的字段
+		for want, got := range map[interface{}]interface{}{
+			"pets@example.com": spec.Info.Contact.Extensions["x-contact"],
+			"spdx":             spec.Info.License.Extensions["x-license"],
+			"guide":            spec.ExternalDocs.Extensions["x-docs"],
+			"primary":          spec.Servers[0].Extensions["x-server"],
+			true:               spec.Servers[0].Variables["region"].Extensions["x-variable"],
+			"self":             oper.Responses["200"].Links["self"].Extensions["x-link"],
+			"png":              item.Put.RequestBody.Content["multipart/form-data"].Encoding["photo"].Extensions["x-encoding"],
+			"dog":              spec.Components.Examples["Pet"].Extensions["x-example"],
+			"all":              schemes["oauth"].Flows.Extensions["x-flows"],
+			"machine":          schemes["oauth"].Flows.ClientCredentials.Extensions["x-flow"],
+		} {
+			require.Equal(t, want, got, path)
+		}
+		name, _ := pet.Properties.Get("name")
+		require.Equal(t, "display", name.(*models.Schema).Extras["x-name"])
+		tags, _ := pet.Properties.Get("tags")
+		require.Equal(t, "tag", tags.(*models.Schema).Items.Extras["x-items"])
+		docs = append(docs, spec)
+	}
+	a, err := json.Marshal(docs[0])
+	require.NoError(t, err)
+	b, err := json.Marshal(docs[1])
+	require.NoError(t, err)
+	require.JSONEq(t, string(a), string(b))
+
+	// 序列化后再解析,扩展字段和properties的顺序不变
+	again, err := Load(strings.NewReader(string(a)))
+	require.NoError(t, err)
+	c, err := json.Marshal(again)
+	require.NoError(t, err)
+	require.Equal(t, string(a), string(c))
+	require.Contains(t, string(c), `"properties":{"name":{"type":"string","x-name":"display"},"id":`)
+	for _, key := range []string{"x-logo", "x-contact", "x-license", "x-docs", "x-server", "x-variable", "x-rate-limit",
+		"x-link", "x-encoding", "x-go-type", "x-name", "x-items", "x-example", "x-flows", "x-flow"} {
+		require.Contains(t, string(c), `"`+key+`":`)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`{"openapi":"3.1.0","info":{"title":"a","version":"1"}}`: `openapi: load: unsupported version "3.1.0", want 3.0.x`,
+		"swagger: \"2.0\"": `openapi: load: unsupported version "", want 3.0.x`,
+		"":                 "openapi: load: empty document",
+		"openapi: 3.0.0\ncomponents:\n  securitySchemes:\n    key:\n      type: apiKey\n": `openapi: load: security scheme "key": `,
+		"openapi: [": "openapi: load: yaml:",
+		"openapi: 3.0.3\ncomponents:\n  securitySchemes:\n    mtls:\n      type: mutualTLS\n": `openapi: load: security scheme "mtls": type mutualTLS requires openapi 3.1`,
+	} {
+		_, err := Load(strings.NewReader(input))
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), want, input)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	f, err := os.Open("fixtures/petstore.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+	spec, err := Load(f)
+	require.NoError(t, err)
+
+	doc := NewDocument("pets", "1.0.0")
+	doc.Register(&TestRouter{Method: "POST", Path: "/user", ReqStruct: ReqStruct{Hello: "a"}})
+	before := doc.Snapshot()
+	require.NoError(t, doc.Merge(spec))
+	s := doc.Snapshot()
+	require.NotNil(t, s.Paths["/pets/{id}"].Get)
+	require.NotNil(t, s.Paths["/user"].Post)
+	require.Contains(t, s.Components.Schemas, "Pet")
+	require.Contains(t, s.Components.SecuritySchemes, "bearer")
+	require.Equal(t, "pets", s.Tags[0].Name)
+	require.Equal(t, "https://{region}.petstore.example.com", s.Servers[0].Url)
+	require.NotContains(t, before.Paths, "/pets/{id}")
+	require.NoError(t, doc.Validate())
+	require.Equal(t, spec.ExternalDocs, s.ExternalDocs)
+
+	// 顶层的security和externalDocs两边不同时返回错误
+	top := &models.OpenAPI{Security: []models.SecurityRequirement{{"bearer": {}}}}
+	require.NoError(t, doc.Merge(top))
+	require.Equal(t, top.Security, doc.Snapshot().Security)
+	require.NoError(t, doc.Merge(top))
+	top.Security = []models.SecurityRequirement{{"apiKey": {}}}
+	require.EqualError(t, doc.Merge(top), `openapi: merge: security conflicts with the existing one`)
+	require.EqualError(t, NewDocument("pets", "1.0.0").Merge(top),
+		`openapi: merge: security: security scheme "apiKey" is not declared in components.securitySchemes`)
+	top = &models.OpenAPI{ExternalDocs: &models.ExternalDocumentation{Url: "https://example.com/other"}}
+	require.EqualError(t, doc.Merge(top), `openapi: merge: externalDocs conflicts with the existing one`)
+	top = &models.OpenAPI{Extensions: map[string]interface{}{"x-owner": "pets"}}
+	require.NoError(t, doc.Merge(top))
+	require.Equal(t, "pets", doc.Snapshot().Extensions["x-owner"])
+	top.Extensions["x-owner"] = "other"
+	require.EqualError(t, doc.Merge(top), `openapi: merge: x-owner conflicts with the existing one`)
+	require.Equal(t, spec.ExternalDocs, doc.Snapshot().ExternalDocs)
+
+	// 已经存在的path合并operation以外的字段
+	extra := &models.OpenAPI{Paths: map[string]*models.PathItem{"/pets/{id}": {
+		Summary:    "a pet",
+		Parameters: []*models.Parameter{spec.Paths["/pets/{id}"].Parameters[0], {Name: "X-Tenant", In: "header"}},
+		Servers:    []*models.Server{{Url: "https://pets.example.com"}},
+		Extensions: map[string]interface{}{"x-owner": "pets"},
+		Delete:     &models.Operation{OperationId: "deletePet"},
+	}}}
+	require.NoError(t, doc.Merge(extra))
+	item := doc.Snapshot().Paths["/pets/{id}"]
+	require.Equal(t, "a pet", item.Summary)
+	require.Len(t, item.Parameters, 2)
+	require.Equal(t, "X-Tenant", item.Parameters[1].Name)
+	require.Equal(t, "https://pets.example.com", item.Servers[0].Url)
+	require.Equal(t, "pets", item.Extensions["x-owner"])
+	require.NotNil(t, item.Get)
+	require.NotNil(t, item.Delete)
+	require.Len(t, spec.Paths["/pets/{id}"].Parameters, 1)
+
+	conflict := &models.OpenAPI{Paths: map[string]*models.PathItem{"/pets/{id}": {Summary: "another pet"}}}
+	require.EqualError(t, doc.Merge(conflict), "openapi: merge: summary of /pets/{id} conflicts with the existing one")
+	conflict.Paths["/pets/{id}"] = &models.PathItem{Parameters: []*models.Parameter{{Name: "id", In: "path"}}}
+	require.EqualError(t, doc.Merge(conflict), "openapi: merge: parameter id in path of /pets/{id} conflicts with the existing one")
+	conflict.Paths["/pets/{id}"] = &models.PathItem{Extensions: map[string]interface{}{"x-owner": "other"}}
+	require.EqualError(t, doc.Merge(conflict), "openapi: merge: x-owner of /pets/{id} conflicts with the existing one")
+
+	// 合并同一个文档:operation已经存在
+	require.EqualError(t, doc.Merge(spec), "openapi: merge: GET /pets/{id} is already registered")
+
+	// operationId重复
+	other := &models.OpenAPI{Paths: map[string]*models.PathItem{
+		"/animals/{id}": {Get: &models.Operation{OperationId: "getPet"}},
+	}}
+	require.EqualError(t, doc.Merge(other), `openapi: merge: operationId "getPet" of GET /animals/{id} is already used by GET /pets/{id}`)
+
+	// 同名但内容不同的component,出错时文档不变
+	other = &models.OpenAPI{
+		Paths: map[string]*models.PathItem{"/animals": {Get: &models.Operation{OperationId: "listAnimals"}}},
+		Components: &models.Components{Schemas: map[string]*models.Schema{
+			"Pet": {Type: "string"},
+		}},
+	}
+	require.EqualError(t, doc.Merge(other), "openapi: merge: components.schemas.Pet conflicts with the existing one")
+	require.NotContains(t, doc.Snapshot().Paths, "/animals")
+
+	other.Components.Schemas["Pet"] = spec.Components.Schemas["Pet"]
+	require.NoError(t, doc.Merge(other))
+	require.Contains(t, doc.Snapshot().Paths, "/animals")
+}
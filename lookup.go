@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// FindOperation 在文档中查找请求对应的operation,返回operation和path参数的值.
+// 多个path模板都能匹配时,优先使用静态部分最多的模板,由*rest注册的模板匹配剩余的整个路径
+func (n *Document) FindOperation(method, path string) (*models.Operation, map[string]string, bool) {
+	return n.findOperation(n.Snapshot(), method, path)
+}
+
+func (n *Document) findOperation(spec *models.OpenAPI, method, path string) (*models.Operation, map[string]string, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	var (
+		found  *models.Operation
+		vars   map[string]string
+		best   string
+		static = -1
+	)
+	for template, item := range spec.Paths {
+		oper := item.GetOperation(method)
+		if oper == nil {
+			continue
+		}
+		v, score, ok := matchPath(template, path, n.catchAll[template])
+		if !ok || score < static || (score == static && template > best) {
+			continue
+		}
+		found, vars, best, static = oper, v, template, score
+	}
+	return found, vars, found != nil
+}
+
+// matchPath 用path模板匹配请求路径,返回参数的值和模板中静态部分的长度.
+// path为转义后的路径,参数的值会被反转义.catchAll为true时最后一段匹配剩余的整个路径
+func matchPath(template, path string, catchAll bool) (map[string]string, int, bool) {
+	ts := strings.Split(strings.Trim(template, "/"), "/")
+	n := -1
+	if catchAll {
+		n = len(ts)
+	}
+	ps := strings.SplitN(strings.Trim(path, "/"), "/", n)
+	if len(ts) != len(ps) {
+		return nil, 0, false
+	}
+	vars := map[string]string{}
+	static := 0
+	for i, t := range ts {
+		if !matchSegment(t, ps[i], vars) {
+			return nil, 0, false
+		}
+		static += staticLen(t)
+	}
+	for name, value := range vars {
+		v, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, 0, false
+		}
+		vars[name] = v
+	}
+	return vars, static, true
+}
+
+// staticLen 返回模板中{}之外的字符数
+func staticLen(template string) int {
+	l := 0
+	depth := 0
+	for _, r := range template {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case depth == 0:
+			l++
+		}
+	}
+	return l
+}
+
+// matchSegment 匹配路径中的一段,模板可以是name,{id}或者{name}.{ext}这种混合形式
+func matchSegment(template, segment string, vars map[string]string) bool {
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			return template == segment
+		}
+		if !strings.HasPrefix(segment, template[:start]) {
+			return false
+		}
+		segment = segment[start:]
+		end := strings.IndexByte(template, '}')
+		if end < start {
+			return false
+		}
+		name := template[start+1 : end]
+		template = template[end+1:]
+		// 参数一直匹配到下一个静态部分
+		next := template
+		if i := strings.IndexByte(next, '{'); i >= 0 {
+			next = next[:i]
+		}
+		var value string
+		if next == "" {
+			if template != "" {
+				return false //两个参数相连时无法区分
+			}
+			value, segment = segment, ""
+		} else {
+			i := strings.Index(segment, next)
+			if i < 0 {
+				return false
+			}
+			value, segment = segment[:i], segment[i:]
+		}
+		if value == "" {
+			return false
+		}
+		vars[name] = value
+	}
+	return segment == ""
+}
@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/Chise1/openapi/validate"
+)
+
+// DefaultMaxBodySize 校验请求时body的默认大小上限
+const DefaultMaxBodySize int64 = 10 << 20
+
+// ValidateOption 设置请求校验的选项,用于ValidateRequests和NewRouter
+type ValidateOption func(c *validateConfig)
+
+type validateConfig struct {
+	maxBodySize int64
+}
+
+// WithMaxBodySize 设置请求body的大小上限,超过时返回413,小于等于0时不限制.默认为DefaultMaxBodySize
+func WithMaxBodySize(size int64) ValidateOption {
+	return func(c *validateConfig) {
+		c.maxBodySize = size
+	}
+}
+
+func newValidateConfig(opts []ValidateOption) validateConfig {
+	c := validateConfig{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// limitBody 限制请求body的大小,超过上限时读取返回*bodyTooLargeError
+func (n validateConfig) limitBody(r *http.Request) {
+	if n.maxBodySize > 0 && r.Body != nil && r.Body != http.NoBody {
+		r.Body = &limitedBody{ReadCloser: r.Body, limit: n.maxBodySize, remaining: n.maxBodySize}
+	}
+}
+
+// bodyTooLargeError 请求body超过了大小上限
+type bodyTooLargeError struct {
+	limit int64
+}
+
+func (n *bodyTooLargeError) Error() string {
+	return fmt.Sprintf("request body is larger than %d bytes", n.limit)
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (n *limitedBody) Read(p []byte) (int, error) {
+	if n.remaining <= 0 {
+		// 再读一个字节判断是否超过上限
+		var b [1]byte
+		count, err := n.ReadCloser.Read(b[:])
+		if count > 0 {
+			return 0, &bodyTooLargeError{limit: n.limit}
+		}
+		return 0, err
+	}
+	if int64(len(p)) > n.remaining {
+		p = p[:n.remaining]
+	}
+	count, err := n.ReadCloser.Read(p)
+	n.remaining -= int64(count)
+	return count, err
+}
+
+// readBodyProblem 读取body出错时的响应,超过大小上限时返回413
+func readBodyProblem(err error) *Problem {
+	if problem := tooLargeProblem(err); problem != nil {
+		return problem
+	}
+	return NewProblem(http.StatusBadRequest, "read body: "+err.Error())
+}
+
+// tooLargeProblem err是因为body超过大小上限时返回413,否则返回nil
+func tooLargeProblem(err error) *Problem {
+	var tooLarge *bodyTooLargeError
+	if errors.As(err, &tooLarge) {
+		return NewProblem(http.StatusRequestEntityTooLarge, tooLarge.Error())
+	}
+	return nil
+}
+
+// emptyBody 判断请求是否没有body,为了判断而读取的一个字节会放回body中
+func emptyBody(r *http.Request) (bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true, nil
+	}
+	var b [1]byte
+	count, err := io.ReadFull(r.Body, b[:])
+	if count == 0 {
+		if err == io.EOF {
+			return true, nil
+		}
+		return false, err
+	}
+	r.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(b[:count]), r.Body), Closer: r.Body}
+	return false, nil
+}
+
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// ValidateRequests 返回一个中间件,按文档中注册的operation校验请求的path,query,header,cookie参数和json body,
+// 校验失败时返回RFC 7807格式的错误,列出所有不合法的参数.文档中没有的请求直接交给next处理.
+// body的大小默认不超过DefaultMaxBodySize,可以用WithMaxBodySize修改
+func (n *Document) ValidateRequests(next http.Handler, opts ...ValidateOption) http.Handler {
+	c := newValidateConfig(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spec := n.Snapshot()
+		oper, vars, ok := n.findOperation(spec, r.Method, r.URL.EscapedPath())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		c.limitBody(r)
+		if problem := n.validateRequest(spec, oper, vars, r); problem != nil {
+			problem.Instance = r.URL.Path
+			problem.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validator 返回快照对应的Validator,快照不变时复用
+func (n *Document) validator(spec *models.OpenAPI) *validate.Validator {
+	n.validatorMu.Lock()
+	defer n.validatorMu.Unlock()
+	if n.validatorSpec != spec {
+		n.validatorSpec = spec
+		n.validatorCache = validate.New(spec.Components.Schemas)
+	}
+	return n.validatorCache
+}
+
+// validateRequest 校验请求,通过时返回nil.json body会被读取并替换为可以重复读取的副本
+func (n *Document) validateRequest(spec *models.OpenAPI, oper *models.Operation, vars map[string]string, r *http.Request) *Problem {
+	v := n.validator(spec)
+	var invalid []InvalidParam
+	source := newParamSource(r, vars)
+	for _, param := range oper.Parameters {
+		value, ok, err := source.value(spec.Components.Schemas, param)
+		if !ok {
+			if param.Required {
+				invalid = append(invalid, InvalidParam{Name: param.Name, In: param.In, Reason: "is required"})
+			}
+			continue
+		}
+		if err != nil {
+			invalid = append(invalid, InvalidParam{Name: param.Name, In: param.In, Reason: err.Error()})
+			continue
+		}
+		for _, e := range v.Validate(param.Schema, value) {
+			invalid = append(invalid, InvalidParam{Name: param.Name + e.Pointer, In: param.In, Reason: e.Message})
+		}
+	}
+	if oper.RequestBody != nil {
+		problem, bodyInvalid := validateBody(v, oper.RequestBody, r)
+		if problem != nil {
+			return problem
+		}
+		invalid = append(invalid, bodyInvalid...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	problem := NewProblem(http.StatusBadRequest, "request validation failed")
+	problem.InvalidParams = invalid
+	return problem
+}
+
+// validateBody 校验json body,其他媒体类型只检查是否声明,不读取body,表单在binder中转换类型后校验
+func validateBody(v *validate.Validator, body *models.RequestBody, r *http.Request) (*Problem, []InvalidParam) {
+	empty, err := emptyBody(r)
+	if err != nil {
+		return readBodyProblem(err), nil
+	}
+	if empty {
+		if body.Required {
+			return nil, []InvalidParam{{Name: "", In: "body", Reason: "is required"}}
+		}
+		return nil, nil
+	}
+	contentType, _, media := matchContentType(body.Content, r.Header.Get("Content-Type"))
+	if media == nil {
+		return NewProblem(http.StatusUnsupportedMediaType, "unsupported content type "+r.Header.Get("Content-Type")), nil
+	}
+	if !isJSON(contentType) {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return readBodyProblem(err), nil
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	instance, err := decodeJSON(b)
+	if err != nil {
+		return nil, []InvalidParam{{Name: "", In: "body", Reason: err.Error()}}
+	}
+	var invalid []InvalidParam
+	for _, e := range v.Validate(media.Schema, instance) {
+		invalid = append(invalid, InvalidParam{Name: e.Pointer, In: "body", Reason: e.Message})
+	}
+	return nil, invalid
+}
+
+// decodeJSON 解码json,数字保留为json.Number
+func decodeJSON(b []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, fmt.Errorf("invalid json: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid json: unexpected data after top-level value")
+	}
+	return instance, nil
+}
+
+// matchContentType 按请求的Content-Type查找声明的媒体类型,支持text/*和*/*这样的范围.
+// 返回请求的媒体类型,匹配到的声明中的key和媒体类型
+func matchContentType(content map[string]*models.MediaType, header string) (string, string, *models.MediaType) {
+	contentType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		contentType = "application/octet-stream"
+	}
+	keys := []string{contentType}
+	if i := strings.IndexByte(contentType, '/'); i > 0 {
+		keys = append(keys, contentType[:i]+"/*")
+	}
+	for _, key := range append(keys, "*/*") {
+		if media, ok := content[key]; ok {
+			return contentType, key, media
+		}
+	}
+	return contentType, "", nil
+}
+
+// isJSON 判断是否为application/json或者+json结尾的媒体类型
+func isJSON(contentType string) bool {
+	return contentType == string(Json) || strings.HasSuffix(contentType, "+json")
+}
@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type CreateOrderPara struct {
+	Shop   string `json:"shop" in:"path" openapi:"pattern=^[a-z]+$"`
+	Limit  int    `json:"limit,omitempty" in:"query" openapi:"gte=1,lte=100"`
+	Dry    bool   `json:"dry,omitempty" in:"query"`
+	Trace  string `json:"X-Trace" in:"header" openapi:"minLen=8"`
+	Region string `json:"region,omitempty" in:"cookie" openapi:"enum=eu,enum=us"`
+}
+
+type CreateOrderItem struct {
+	Sku      string `json:"sku" openapi:"minLen=3,maxLen=8"`
+	Quantity int    `json:"quantity" openapi:"gt=0"`
+}
+
+type CreateOrderBody struct {
+	Items []CreateOrderItem `json:"items"`
+	Note  string            `json:"note,omitempty" openapi:"maxLen=5"`
+}
+
+func newOrderDocument() *Document {
+	doc := NewDocument("orders", "1.0.0")
+	doc.Register(&TestRouter{
+		Method:    "POST",
+		Path:      "/shops/{shop}/orders",
+		Param:     CreateOrderPara{},
+		ReqStruct: CreateOrderBody{},
+	})
+	return doc
+}
+
+func TestValidateRequests(t *testing.T) {
+	doc := newOrderDocument()
+	called := 0
+	var body []byte
+	handler := doc.ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest("POST", "/shops/acme/orders?limit=10&dry=true", strings.NewReader(`{"items":[{"sku":"abc","quantity":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Trace", "12345678")
+	req.AddCookie(&http.Cookie{Name: "region", Value: "eu"})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.Equal(t, 1, called)
+	require.JSONEq(t, `{"items":[{"sku":"abc","quantity":1}]}`, string(body))
+
+	req = httptest.NewRequest("POST", "/shops/ACME/orders?limit=0&dry=maybe", strings.NewReader(`{"items":[{"sku":"a","quantity":0,"color":"red"}],"note":"too long"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.AddCookie(&http.Cookie{Name: "region", Value: "cn"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	require.Equal(t, 1, called)
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, "/shops/ACME/orders", problem.Instance)
+	require.Equal(t, []InvalidParam{
+		{Name: "shop", In: "path", Reason: `must match pattern "^[a-z]+$"`},
+		{Name: "limit", In: "query", Reason: "must be greater than or equal to 1"},
+		{Name: "dry", In: "query", Reason: `must be boolean, got "maybe"`},
+		{Name: "X-Trace", In: "header", Reason: "is required"},
+		{Name: "region", In: "cookie", Reason: "must be one of [eu us]"},
+		{Name: "/items/0/color", In: "body", Reason: "is not allowed"},
+		{Name: "/items/0/quantity", In: "body", Reason: "must be greater than 0"},
+		{Name: "/items/0/sku", In: "body", Reason: "must be at least 3 characters long"},
+		{Name: "/note", In: "body", Reason: "must be at most 5 characters long"},
+	}, problem.InvalidParams)
+
+	req = httptest.NewRequest("POST", "/shops/acme/orders", strings.NewReader(`items=1`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Trace", "12345678")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/unknown", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestValidateRequestsNumbers(t *testing.T) {
+	handler := newOrderDocument().ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	for _, limit := range []string{"NaN", "Inf", "-Inf", "1_000", "0x10", "1e1", "1.0", "01", "+1", ".5"} {
+		req := httptest.NewRequest("POST", "/shops/acme/orders?limit="+url.QueryEscape(limit), strings.NewReader(`{"items":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Trace", "12345678")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code, limit)
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		require.Equal(t, []InvalidParam{
+			{Name: "limit", In: "query", Reason: fmt.Sprintf("must be integer, got %q", limit)},
+		}, problem.InvalidParams, limit)
+	}
+
+	number := &models.Schema{Type: "number"}
+	for _, v := range []string{"0", "-1", "1.5", "1e3", "-2.5E-3"} {
+		value, err := coerceValue(nil, number, v)
+		require.NoError(t, err, v)
+		require.Equal(t, json.Number(v), value)
+	}
+	for _, v := range []string{"NaN", "Inf", "1_000", "0x10", "1.", "1e400", ""} {
+		_, err := coerceValue(nil, number, v)
+		require.EqualError(t, err, fmt.Sprintf("must be number, got %q", v))
+	}
+}
+
+func TestValidateRequestsMaxBodySize(t *testing.T) {
+	doc := newOrderDocument()
+	body := `{"items":[{"sku":"abc","quantity":1}]}`
+	for size, want := range map[int64]int{
+		int64(len(body)):     http.StatusCreated,
+		int64(len(body)) - 1: http.StatusRequestEntityTooLarge,
+		0:                    http.StatusCreated,
+	} {
+		handler := doc.ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}), WithMaxBodySize(size))
+		req := httptest.NewRequest("POST", "/shops/acme/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Trace", "12345678")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, want, rec.Code, "size %d: %s", size, rec.Body.String())
+		if want == http.StatusRequestEntityTooLarge {
+			var problem Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			require.Equal(t, fmt.Sprintf("request body is larger than %d bytes", size), problem.Detail)
+		}
+	}
+}
+
+func TestFindOperation(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/users/{id}", Param: PathID{}}, OperationID: "getUser"})
+	doc.Register(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/users/me"}, OperationID: "getMe"})
+	doc.Register(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/files/{name}.{ext}", Param: PathFile{}}, OperationID: "getFile"})
+
+	oper, vars, ok := doc.FindOperation("GET", "/users/me")
+	require.True(t, ok)
+	require.Equal(t, "getMe", oper.OperationId)
+	require.Empty(t, vars)
+
+	oper, vars, ok = doc.FindOperation("GET", "/users/a%2Fb")
+	require.True(t, ok)
+	require.Equal(t, "getUser", oper.OperationId)
+	require.Equal(t, map[string]string{"id": "a/b"}, vars)
+
+	oper, vars, ok = doc.FindOperation("GET", "/files/report.tar.gz")
+	require.True(t, ok)
+	require.Equal(t, "getFile", oper.OperationId)
+	require.Equal(t, map[string]string{"name": "report", "ext": "tar.gz"}, vars)
+
+	_, _, ok = doc.FindOperation("POST", "/users/me")
+	require.False(t, ok)
+}
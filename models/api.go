@@ -3,20 +3,16 @@ package models
 type APIKeyIn string
 
 const (
-	APIKeyInQuery  = "query"
-	APIKeyInHeader = "header"
-	APIKeyInCookie = "cookie"
+	APIKeyInQuery  APIKeyIn = "query"
+	APIKeyInHeader APIKeyIn = "header"
+	APIKeyInCookie APIKeyIn = "cookie"
 )
 
-type APIKey struct {
-	Type        SecuritySchemeType `json:"type"` //默认值为APIKeyInQuery
-	In          APIKeyIn           `json:"in"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-}
-
-func NewAPIKey() APIKey {
-	return APIKey{
+// NewAPIKey 创建apiKey安全方案,name为参数名,in为参数位置
+func NewAPIKey(name string, in APIKeyIn) *SecurityScheme {
+	return &SecurityScheme{
 		Type: ApiKey,
+		Name: name,
+		In:   in,
 	}
 }
@@ -1,49 +1,64 @@
 package models
 
-type OAuthFlowImplicit struct {
-	RefreshUrl       string            `json:"refreshUrl"`
-	Scopes           map[string]string `json:"scopes"`
-	AuthorizationUrl string            `json:"authorizationUrl"`
-}
-type OAuthFlowPassword struct {
-	RefreshUrl string            `json:"refreshUrl"`
-	Scopes     map[string]string `json:"scopes"`
-	TokenUrl   string            `json:"tokenUrl"`
-}
-type OAuthFlowClientCredentials struct {
-	RefreshUrl string            `json:"refreshUrl"`
-	Scopes     map[string]string `json:"scopes"`
-	TokenUrl   string            `json:"tokenUrl"`
-}
-type OAuthFlowAuthorizationCode struct {
-	RefreshUrl       string            `json:"refreshUrl"`
-	Scopes           map[string]string `json:"scopes"`
-	AuthorizationUrl string            `json:"authorizationUrl"`
-	TokenUrl         string            `json:"tokenUrl"`
+import (
+	"fmt"
+)
+
+type OAuthFlow struct {
+	AuthorizationUrl string                 `json:"authorizationUrl,omitempty"` //implicit, authorizationCode REQUIRED. The authorization URL to be used for this flow. This MUST be in the form of a URL.
+	TokenUrl         string                 `json:"tokenUrl,omitempty"`         //password, clientCredentials, authorizationCode REQUIRED. The token URL to be used for this flow. This MUST be in the form of a URL.
+	RefreshUrl       string                 `json:"refreshUrl,omitempty"`       //The URL to be used for obtaining refresh tokens. This MUST be in the form of a URL.
+	Scopes           map[string]string      `json:"scopes"`                     //REQUIRED. The available scopes for the OAuth2 security scheme. A map between the scope name and a short description for it. The map MAY be empty.
+	Extensions       map[string]interface{} `json:"-"`                          //This object MAY be extended with Specification Extensions, the keys start with x-.
 }
-type OAuthFlows struct {
-	Implicit          *OAuthFlowImplicit          `json:"implicit"`
-	Password          *OAuthFlowPassword          `json:"password"`
-	ClientCredentials *OAuthFlowClientCredentials `json:"clientCredentials"`
-	AuthorizationCode *OAuthFlowAuthorizationCode `json:"authorizationCode"`
+
+func (n OAuthFlow) MarshalJSON() ([]byte, error) {
+	type Type_ OAuthFlow
+	if n.Scopes == nil {
+		n.Scopes = map[string]string{}
+	}
+	return marshalWithExtensions(Type_(n), n.Extensions)
 }
 
-type OAuth2 struct {
-	Type        SecuritySchemeType `json:"type"`
-	Description string             `json:"description"`
-	Flows       OAuthFlows         `json:"flows"`
+type OAuthFlows struct {
+	Implicit          *OAuthFlow             `json:"implicit,omitempty"`
+	Password          *OAuthFlow             `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow             `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow             `json:"authorizationCode,omitempty"`
+	Extensions        map[string]interface{} `json:"-"` //This object MAY be extended with Specification Extensions, the keys start with x-.
 }
 
-func NewOAuth2() OAuth2 {
-	return OAuth2{Type: Oauth2}
+func (n *OAuthFlows) validate() error {
+	if n.Implicit == nil && n.Password == nil && n.ClientCredentials == nil && n.AuthorizationCode == nil {
+		return fmt.Errorf("oauth2 security scheme requires at least one flow")
+	}
+	if n.Implicit != nil && n.Implicit.AuthorizationUrl == "" {
+		return fmt.Errorf("oauth2 implicit flow requires authorizationUrl")
+	}
+	if n.Password != nil && n.Password.TokenUrl == "" {
+		return fmt.Errorf("oauth2 password flow requires tokenUrl")
+	}
+	if n.ClientCredentials != nil && n.ClientCredentials.TokenUrl == "" {
+		return fmt.Errorf("oauth2 clientCredentials flow requires tokenUrl")
+	}
+	if n.AuthorizationCode != nil && (n.AuthorizationCode.AuthorizationUrl == "" || n.AuthorizationCode.TokenUrl == "") {
+		return fmt.Errorf("oauth2 authorizationCode flow requires authorizationUrl and tokenUrl")
+	}
+	return nil
 }
 
-type OpenIdConnect struct {
-	Type        SecuritySchemeType `json:"type"`
-	Description string             `json:"description"`
-	Flows       OAuthFlows         `json:"flows"`
+// NewOAuth2 创建oauth2安全方案
+func NewOAuth2(flows OAuthFlows) *SecurityScheme {
+	return &SecurityScheme{
+		Type:  Oauth2,
+		Flows: &flows,
+	}
 }
 
-func NewOpenIdConnect() OAuth2 {
-	return OAuth2{Type: Openidconnect}
+// NewOpenIdConnect 创建openIdConnect安全方案,url为OpenID Connect的发现地址
+func NewOpenIdConnect(url string) *SecurityScheme {
+	return &SecurityScheme{
+		Type:             Openidconnect,
+		OpenIdConnectUrl: url,
+	}
 }
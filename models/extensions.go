@@ -0,0 +1,347 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// extensionPrefix 扩展字段的前缀
+const extensionPrefix = "x-"
+
+// marshalExtensions 把扩展字段追加到json对象b中
+func marshalExtensions(b []byte, extensions map[string]interface{}) ([]byte, error) {
+	if len(extensions) == 0 {
+		return b, nil
+	}
+	m, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 2 {
+		return m, nil
+	}
+	b = append(b[:len(b)-1], ',')
+	return append(b, m[1:]...), nil
+}
+
+// marshalWithExtensions 序列化v并追加扩展字段,v是去掉了MarshalJSON方法的别名类型
+func marshalWithExtensions(v interface{}, extensions map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtensions(b, extensions)
+}
+
+// unmarshalWithExtensions 把json对象b解码到v中,x-开头的字段保存到extensions,v是去掉了UnmarshalJSON方法的别名类型的指针
+func unmarshalWithExtensions(b []byte, v interface{}, extensions *map[string]interface{}) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	var err error
+	*extensions, err = unmarshalExtensions(b, reflect.TypeOf(v).Elem())
+	return err
+}
+
+// unknownFields 返回json对象b中t的json tag里没有的字段,keep为nil时返回全部,否则只返回keep为true的字段
+func unknownFields(b []byte, t reflect.Type, keep func(key string) bool) (map[string]interface{}, error) {
+	keys, values, err := objectFields(b)
+	if err != nil {
+		return nil, err
+	}
+	known := jsonFields(t)
+	var fields map[string]interface{}
+	for _, key := range keys {
+		if known[key] || (keep != nil && !keep(key)) {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(values[key], &v); err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			fields = map[string]interface{}{}
+		}
+		fields[key] = v
+	}
+	return fields, nil
+}
+
+// unmarshalExtensions 读取json对象b中x-开头的扩展字段
+func unmarshalExtensions(b []byte, t reflect.Type) (map[string]interface{}, error) {
+	return unknownFields(b, t, func(key string) bool {
+		return strings.HasPrefix(key, extensionPrefix)
+	})
+}
+
+// objectFields 按顺序返回json对象的key和未解码的值
+func objectFields(b []byte) ([]string, map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("models: expected an object, got %v", token)
+	}
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := token.(string)
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+	return keys, values, nil
+}
+
+var fieldCache sync.Map // reflect.Type -> map[string]bool
+
+// jsonFields 返回结构体的json tag中的名字,包括嵌入的结构体
+func jsonFields(t reflect.Type) map[string]bool {
+	if v, ok := fieldCache.Load(t); ok {
+		return v.(map[string]bool)
+	}
+	fields := map[string]bool{}
+	for _, f := range reflect.VisibleFields(t) {
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if f.PkgPath != "" || name == "-" || (f.Anonymous && name == "") {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+func (n *OpenAPI) MarshalJSON() ([]byte, error) {
+	type object OpenAPI
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *OpenAPI) UnmarshalJSON(b []byte) error {
+	type object OpenAPI
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Info) MarshalJSON() ([]byte, error) {
+	type object Info
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Info) UnmarshalJSON(b []byte) error {
+	type object Info
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Server) MarshalJSON() ([]byte, error) {
+	type object Server
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Server) UnmarshalJSON(b []byte) error {
+	type object Server
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Tag) MarshalJSON() ([]byte, error) {
+	type object Tag
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Tag) UnmarshalJSON(b []byte) error {
+	type object Tag
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *PathItem) MarshalJSON() ([]byte, error) {
+	type object PathItem
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *PathItem) UnmarshalJSON(b []byte) error {
+	type object PathItem
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Operation) MarshalJSON() ([]byte, error) {
+	type object Operation
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Operation) UnmarshalJSON(b []byte) error {
+	type object Operation
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Parameter) MarshalJSON() ([]byte, error) {
+	type object Parameter
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Parameter) UnmarshalJSON(b []byte) error {
+	type object Parameter
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *RequestBody) MarshalJSON() ([]byte, error) {
+	type object RequestBody
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *RequestBody) UnmarshalJSON(b []byte) error {
+	type object RequestBody
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *MediaType) MarshalJSON() ([]byte, error) {
+	type object MediaType
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *MediaType) UnmarshalJSON(b []byte) error {
+	type object MediaType
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Response) MarshalJSON() ([]byte, error) {
+	type object Response
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Response) UnmarshalJSON(b []byte) error {
+	type object Response
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Header) MarshalJSON() ([]byte, error) {
+	type object Header
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Header) UnmarshalJSON(b []byte) error {
+	type object Header
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Components) MarshalJSON() ([]byte, error) {
+	type object Components
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Components) UnmarshalJSON(b []byte) error {
+	type object Components
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *SecurityScheme) MarshalJSON() ([]byte, error) {
+	type object SecurityScheme
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *SecurityScheme) UnmarshalJSON(b []byte) error {
+	type object SecurityScheme
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n ServerVariable) MarshalJSON() ([]byte, error) {
+	type object ServerVariable
+	return marshalWithExtensions(object(n), n.Extensions)
+}
+
+func (n *ServerVariable) UnmarshalJSON(b []byte) error {
+	type object ServerVariable
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Contact) MarshalJSON() ([]byte, error) {
+	type object Contact
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Contact) UnmarshalJSON(b []byte) error {
+	type object Contact
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *License) MarshalJSON() ([]byte, error) {
+	type object License
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *License) UnmarshalJSON(b []byte) error {
+	type object License
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *ExternalDocumentation) MarshalJSON() ([]byte, error) {
+	type object ExternalDocumentation
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *ExternalDocumentation) UnmarshalJSON(b []byte) error {
+	type object ExternalDocumentation
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n Example) MarshalJSON() ([]byte, error) {
+	type object Example
+	return marshalWithExtensions(object(n), n.Extensions)
+}
+
+func (n *Example) UnmarshalJSON(b []byte) error {
+	type object Example
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Encoding) MarshalJSON() ([]byte, error) {
+	type object Encoding
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Encoding) UnmarshalJSON(b []byte) error {
+	type object Encoding
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *Link) MarshalJSON() ([]byte, error) {
+	type object Link
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *Link) UnmarshalJSON(b []byte) error {
+	type object Link
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *OAuthFlows) MarshalJSON() ([]byte, error) {
+	type object OAuthFlows
+	return marshalWithExtensions((*object)(n), n.Extensions)
+}
+
+func (n *OAuthFlows) UnmarshalJSON(b []byte) error {
+	type object OAuthFlows
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
+
+func (n *OAuthFlow) UnmarshalJSON(b []byte) error {
+	type object OAuthFlow
+	return unmarshalWithExtensions(b, (*object)(n), &n.Extensions)
+}
@@ -1,21 +1,26 @@
 package models
 
-type HTTPBase struct {
-	Type        SecuritySchemeType `json:"type"`
-	Description string             `json:"description"`
-	Schema      string             `json:"schema"`
+// NewHTTP 创建http安全方案,scheme为RFC7235中的认证方式,例如basic,bearer,digest
+func NewHTTP(scheme string) *SecurityScheme {
+	return &SecurityScheme{
+		Type:   Http,
+		Scheme: scheme,
+	}
 }
 
-type HTTPBearer struct {
-	Type         SecuritySchemeType `json:"type"`
-	Description  string             `json:"description"`
-	Schema       string             `json:"schema"`
-	BearerFormat string             `json:"bearerFormat"`
+// NewHTTPBasic 创建basic认证
+func NewHTTPBasic() *SecurityScheme {
+	return NewHTTP("basic")
 }
 
-func NewHTTPBearer() HTTPBearer {
-	return HTTPBearer{
-		Schema: "bearer",
-		Type:   Http,
-	}
+// NewHTTPDigest 创建digest认证
+func NewHTTPDigest() *SecurityScheme {
+	return NewHTTP("digest")
+}
+
+// NewHTTPBearer 创建bearer认证,bearerFormat为token格式的提示,例如JWT,可以为空
+func NewHTTPBearer(bearerFormat string) *SecurityScheme {
+	s := NewHTTP("bearer")
+	s.BearerFormat = bearerFormat
+	return s
 }
@@ -2,17 +2,18 @@ package models
 
 type Parameter struct {
 	// Parameter
-	Name          string                `json:"name,omitempty"`
-	In            string                `json:"in,omitempty"`          //REQUIRED. The location of the parameter. Possible values are "query", "header", "path" or "cookie".
-	Description   string                `json:"description,omitempty"` //A brief description of the parameter. This could contain examples of use. CommonMark syntax MAY be used for rich text representation.
-	Required      bool                  `json:"required,omitempty"`    //Determines whether this parameter is mandatory. If the parameter location is "path", this property is REQUIRED and its value MUST be true. Otherwise, the property MAY be included and its default value is false.
-	Deprecated    bool                  `json:"deprecated,omitempty"`  //Specifies that a parameter is deprecated and SHOULD be transitioned out of usage. Default value is false.
-	Style         string                `json:"style,omitempty"`
-	Explode       bool                  `json:"explode,omitempty"`
-	AllowReserved bool                  `json:"allowReserved,omitempty"`
-	Schema        *Schema               `json:"schema,omitempty,omitempty"`
-	Example       interface{}           `json:"example,omitempty"`
-	Examples      map[string]*Example   `json:"examples,omitempty"` //暂不支持
-	Content       map[string]*MediaType `json:"content,omitempty"`
-	Ref           string                `json:"$ref,omitempty"` //REQUIRED. The reference identifier. This MUST be in the form of a URI.
+	Name          string                 `json:"name,omitempty"`
+	In            string                 `json:"in,omitempty"`            //REQUIRED. The location of the parameter. Possible values are "query", "header", "path" or "cookie".
+	Description   string                 `json:"description,omitempty"`   //A brief description of the parameter. This could contain examples of use. CommonMark syntax MAY be used for rich text representation.
+	Required      bool                   `json:"required,omitempty"`      //Determines whether this parameter is mandatory. If the parameter location is "path", this property is REQUIRED and its value MUST be true. Otherwise, the property MAY be included and its default value is false.
+	Deprecated    bool                   `json:"deprecated,omitempty"`    //Specifies that a parameter is deprecated and SHOULD be transitioned out of usage. Default value is false.
+	Style         string                 `json:"style,omitempty"`         //Describes how the parameter value will be serialized depending on the type of the parameter value. Default values (based on value of in): for query - form; for path - simple; for header - simple; for cookie - form.
+	Explode       *bool                  `json:"explode,omitempty"`       //When this is true, parameter values of type array or object generate separate parameters for each value of the array or key-value pair of the map. When style is form, the default value is true. For all other styles, the default value is false.
+	AllowReserved bool                   `json:"allowReserved,omitempty"` //Determines whether the parameter value SHOULD allow reserved characters, as defined by RFC3986 :/?#[]@!$&'()*+,;= to be included without percent-encoding. This property only applies to parameters with an in value of query.
+	Schema        *Schema                `json:"schema,omitempty,omitempty"`
+	Example       interface{}            `json:"example,omitempty"`
+	Examples      map[string]*Example    `json:"examples,omitempty"` //暂不支持
+	Content       map[string]*MediaType  `json:"content,omitempty"`
+	Ref           string                 `json:"$ref,omitempty"` //REQUIRED. The reference identifier. This MUST be in the form of a URI.
+	Extensions    map[string]interface{} `json:"-"`              //This object MAY be extended with Specification Extensions, the keys start with x-.
 }
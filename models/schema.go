@@ -58,7 +58,7 @@ type Schema struct {
 	Default     interface{} `json:"default,omitempty"`
 
 	Format   string        `json:"format,omitempty"` // 一些固定类型的匹配，比如email，ip，uuid，datetime等
-	Example  *Example      `json:"example,omitempty"`
+	Example  interface{}   `json:"example,omitempty"`
 	Examples []interface{} `json:"examples,omitempty,omitempty"` // 例子
 	//go不支持多态（这个字段没啥意义）
 	//Discriminator Discriminator         `json:"discriminator,omitempty"`      //    Adds support for polymorphism.The discriminator is an object name that is used to differentiate between other schemas which may satisfy the payload description.See Composition and Inheritance for more details.
@@ -105,6 +105,39 @@ func (t *Schema) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// UnmarshalJSON 解析schema,properties保持原来的顺序,值为*Schema.未知的字段保存在Extras中
+func (t *Schema) UnmarshalJSON(b []byte) error {
+	type Type_ Schema
+	aux := struct {
+		*Type_
+		Properties json.RawMessage `json:"properties,omitempty"`
+	}{Type_: (*Type_)(t)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	t.Properties = nil
+	if len(aux.Properties) > 0 && string(aux.Properties) != "null" {
+		keys, values, err := objectFields(aux.Properties)
+		if err != nil {
+			return err
+		}
+		t.Properties = orderedmap.New()
+		for _, key := range keys {
+			property := &Schema{}
+			if err := json.Unmarshal(values[key], property); err != nil {
+				return err
+			}
+			t.Properties.Set(key, property)
+		}
+	}
+	extras, err := unknownFields(b, reflect.TypeOf(Type_{}), nil)
+	if err != nil {
+		return err
+	}
+	t.Extras = extras
+	return nil
+}
+
 // StructKeywordsFromTags 解析结构体字段的tag
 func (t *Schema) StructKeywordsFromTags(f reflect.StructField, parentType *Schema, propertyName string) {
 	t.Description = f.Tag.Get(Description)
@@ -1,19 +1,93 @@
 package models
 
-type SecurityRequirementObject struct {
-	names map[string][]string //Each name MUST correspond to a security scheme which is declared in the Security Schemes under the Components Object. If the security scheme is of type "oauth2" or "openIdConnect", then the value is a list of scope names required for the execution, and the list MAY be empty if authorization does not require a specified scope. For other security scheme types, the array MAY contain a list of role names which are required for the execution, but are not otherwise defined or exchanged in-band.
-}
+import (
+	"fmt"
+	"strings"
+)
+
+// SecurityRequirement Each name MUST correspond to a security scheme which is declared in the Security Schemes under the Components Object. If the security scheme is of type "oauth2" or "openIdConnect", then the value is a list of scope names required for the execution, and the list MAY be empty if authorization does not require a specified scope. For other security scheme types, the array MAY contain a list of role names which are required for the execution, but are not otherwise defined or exchanged in-band.
+// An empty SecurityRequirement{} makes security optional.
+type SecurityRequirement map[string][]string
 
 type SecuritySchemeType string
 
 const (
-	ApiKey        = "apiKey"
-	Http          = "http"
-	Oauth2        = "oauth2"
-	Openidconnect = "openIdConnect"
+	ApiKey        SecuritySchemeType = "apiKey"
+	Http          SecuritySchemeType = "http"
+	Oauth2        SecuritySchemeType = "oauth2"
+	Openidconnect SecuritySchemeType = "openIdConnect"
+	MutualTLS     SecuritySchemeType = "mutualTLS"
 )
 
-type SecurityBase struct {
-	Type        SecuritySchemeType `json:"type"`
-	Description string             `json:"description"`
+// SecurityScheme Defines a security scheme that can be used by the operations. 使用NewAPIKey,NewHTTPBearer等函数创建
+type SecurityScheme struct {
+	Type             SecuritySchemeType     `json:"type"`                       //REQUIRED. The type of the security scheme. Valid values are "apiKey", "http", "mutualTLS", "oauth2", "openIdConnect".
+	Description      string                 `json:"description,omitempty"`      //A description for security scheme. CommonMark syntax MAY be used for rich text representation.
+	Name             string                 `json:"name,omitempty"`             //apiKey REQUIRED. The name of the header, query or cookie parameter to be used.
+	In               APIKeyIn               `json:"in,omitempty"`               //apiKey REQUIRED. The location of the API key. Valid values are "query", "header" or "cookie".
+	Scheme           string                 `json:"scheme,omitempty"`           //http REQUIRED. The name of the HTTP Authorization scheme to be used in the Authorization header as defined in RFC7235. The values used SHOULD be registered in the IANA Authentication Scheme registry.
+	BearerFormat     string                 `json:"bearerFormat,omitempty"`     //http ("bearer") A hint to the client to identify how the bearer token is formatted. Bearer tokens are usually generated by an authorization server, so this information is primarily for documentation purposes.
+	Flows            *OAuthFlows            `json:"flows,omitempty"`            //oauth2 REQUIRED. An object containing configuration information for the flow types supported.
+	OpenIdConnectUrl string                 `json:"openIdConnectUrl,omitempty"` //openIdConnect REQUIRED. OpenId Connect URL to discover OAuth2 configuration values. This MUST be in the form of a URL.
+	Ref              string                 `json:"$ref,omitempty"`
+	Extensions       map[string]interface{} `json:"-"` //This object MAY be extended with Specification Extensions, the keys start with x-.
+}
+
+// Validate 按type检查必填字段
+func (n *SecurityScheme) Validate() error {
+	if n.Ref != "" {
+		return nil
+	}
+	switch n.Type {
+	case ApiKey:
+		if n.Name == "" {
+			return fmt.Errorf("apiKey security scheme requires name")
+		}
+		switch n.In {
+		case APIKeyInQuery, APIKeyInHeader, APIKeyInCookie:
+		default:
+			return fmt.Errorf("apiKey security scheme requires in to be query, header or cookie, got %q", n.In)
+		}
+	case Http:
+		if n.Scheme == "" {
+			return fmt.Errorf("http security scheme requires scheme")
+		}
+		if n.BearerFormat != "" && !strings.EqualFold(n.Scheme, "bearer") {
+			return fmt.Errorf("bearerFormat only applies to the bearer scheme, got %q", n.Scheme)
+		}
+	case Oauth2:
+		if n.Flows == nil {
+			return fmt.Errorf("oauth2 security scheme requires flows")
+		}
+		return n.Flows.validate()
+	case Openidconnect:
+		if n.OpenIdConnectUrl == "" {
+			return fmt.Errorf("openIdConnect security scheme requires openIdConnectUrl")
+		}
+	case MutualTLS:
+	default:
+		return fmt.Errorf("unknown security scheme type %q", n.Type)
+	}
+	return nil
+}
+
+// HasScope 判断oauth2的flows中是否定义了scope
+func (n *SecurityScheme) HasScope(scope string) bool {
+	if n.Flows == nil {
+		return false
+	}
+	for _, flow := range []*OAuthFlow{n.Flows.Implicit, n.Flows.Password, n.Flows.ClientCredentials, n.Flows.AuthorizationCode} {
+		if flow == nil {
+			continue
+		}
+		if _, ok := flow.Scopes[scope]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMutualTLS 创建mutualTLS安全方案,只能用于openapi 3.1的文档
+func NewMutualTLS() *SecurityScheme {
+	return &SecurityScheme{Type: MutualTLS}
 }
@@ -1,7 +1,8 @@
 package models
 
 type Tag struct {
-	Name         string                `json:"name"`         //	REQUIRED. The name of the tag.
-	Description  string                `json:"description"`  //	A description for the tag. CommonMark syntax MAY be used for rich text representation.
-	ExternalDocs ExternalDocumentation `json:"externalDocs"` //	Additional external documentation for this tag.
+	Name         string                 `json:"name"`                   //	REQUIRED. The name of the tag.
+	Description  string                 `json:"description,omitempty"`  //	A description for the tag. CommonMark syntax MAY be used for rich text representation.
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty"` //	Additional external documentation for this tag.
+	Extensions   map[string]interface{} `json:"-"`                      //This object MAY be extended with Specification Extensions, the keys start with x-.
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML 按json输出的字段顺序生成yaml,properties保持声明的顺序,Extras中的扩展字段也会输出
+func (n *OpenAPI) MarshalYAML() (interface{}, error) {
+	return yamlNode(n)
+}
+
+// MarshalYAML 和OpenAPI.MarshalYAML一样,按json输出的字段顺序生成yaml
+func (t *Schema) MarshalYAML() (interface{}, error) {
+	return yamlNode(t)
+}
+
+// WriteYAML 把文档写为缩进两个空格的block风格yaml
+func (n *OpenAPI) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(n); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// yamlNode 把v的json转换为yaml节点.json是yaml的子集,解析后的节点保留了key的顺序,
+// 再把flow风格改为block风格
+func yamlNode(v interface{}) (*yaml.Node, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	blockStyle(node)
+	return node, nil
+}
+
+func blockStyle(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		node.Style = 0
+		if node.Tag == "!!str" && strings.Contains(node.Value, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+	case yaml.MappingNode, yaml.SequenceNode:
+		// 空的对象和数组保持{}和[]
+		if len(node.Content) == 0 {
+			node.Style = yaml.FlowStyle
+			return
+		}
+		node.Style = 0
+		for _, child := range node.Content {
+			blockStyle(child)
+		}
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"testing"
 )
 
-type Handler func(context.Context, interface{}, interface{}) (interface{}, interface{})
-
 type TestRouter struct {
 	Description string
 	Method      string
@@ -77,6 +75,6 @@ func TestRegister2Openapi(t *testing.T) {
 		},
 	}
 	Register2Openapi(&route)
-	r, _ := json.Marshal(OPENAPI)
+	r, _ := json.Marshal(DefaultDocument.Snapshot())
 	fmt.Println(string(r))
 }
@@ -0,0 +1,103 @@
+// Package openapitest 在测试中检查handler的响应是否和openapi文档中声明的一致
+package openapitest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Chise1/openapi"
+)
+
+// TB testing.TB中用到的方法
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// CheckResponse 按文档校验rec中记录的响应,r为产生这个响应的请求.
+// 状态码未声明,content type,body或者必须的header不符合时调用t.Errorf并返回false
+func CheckResponse(t TB, doc *openapi.Document, r *http.Request, rec *httptest.ResponseRecorder) bool {
+	t.Helper()
+	res := rec.Result()
+	if err := doc.ValidateResponse(r, res.StatusCode, res.Header, rec.Body.Bytes()); err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	return true
+}
+
+// Handler 包装handler,每个响应在写出之前都用CheckResponse校验
+func Handler(t TB, doc *openapi.Document, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		CheckResponse(t, doc, r, rec)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	})
+}
+
+// UpdateGoldenEnv 设置了这个环境变量时Golden用当前的文档覆盖golden文件
+const UpdateGoldenEnv = "OPENAPI_UPDATE_GOLDEN"
+
+// Golden 比较文档的规范形式和golden文件,文件扩展名为.yaml或者.yml时使用yaml,其他使用json.
+// 不一致时调用t.Errorf并返回false,设置了OPENAPI_UPDATE_GOLDEN环境变量时改为写入文件
+func Golden(t TB, doc *openapi.Document, path string) bool {
+	t.Helper()
+	var got []byte
+	var err error
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		got, err = doc.CanonicalYAML()
+	default:
+		got, err = doc.CanonicalJSON()
+	}
+	if err != nil {
+		t.Errorf("openapitest: %v", err)
+		return false
+	}
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("openapitest: %v", err)
+			return false
+		}
+		return true
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("openapitest: %v, run with %s=1 to create it", err, UpdateGoldenEnv)
+		return false
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("openapitest: document does not match %s, run with %s=1 to update it:\n%s", path, UpdateGoldenEnv, lineDiff(string(want), string(got)))
+		return false
+	}
+	return true
+}
+
+// lineDiff 返回第一处不同的行
+func lineDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var a, b string
+		if i < len(w) {
+			a = w[i]
+		}
+		if i < len(g) {
+			b = g[i]
+		}
+		if a != b {
+			return fmt.Sprintf("line %d:\n-%s\n+%s", i+1, a, b)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package openapitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Chise1/openapi"
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type User struct {
+	ID   int    `json:"id" openapi:"gte=1"`
+	Name string `json:"name"`
+}
+
+type UserPara struct {
+	ID int `json:"id" in:"path"`
+}
+
+type userRoute struct{}
+
+func (userRoute) GetDescription() string  { return "get user" }
+func (userRoute) GetMethod() string       { return "GET" }
+func (userRoute) GetPath() string         { return "/users/{id}" }
+func (userRoute) GetReqPara() interface{} { return UserPara{} }
+func (userRoute) GetReqBody() interface{} { return nil }
+func (userRoute) GetResPara() interface{} { return nil }
+func (userRoute) GetResBody() map[int]interface{} {
+	return map[int]interface{}{200: User{ID: 1, Name: "a"}}
+}
+
+// recorder 记录Errorf的调用
+type recorder struct {
+	errors []string
+}
+
+func (n *recorder) Helper() {}
+func (n *recorder) Errorf(format string, args ...interface{}) {
+	n.errors = append(n.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckResponse(t *testing.T) {
+	doc := openapi.NewDocument("users", "1.0.0")
+	doc.Register(userRoute{})
+	body := `{"id":1,"name":"a"}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("case") {
+		case "status":
+			w.WriteHeader(http.StatusNotFound)
+		case "type":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(body))
+		case "body":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":0,"extra":true}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}
+	})
+
+	for query, want := range map[string][]string{
+		"":       nil,
+		"status": {"openapi: response 404 for GET /users/1: status is not declared"},
+		"type":   {`openapi: response 200 for GET /users/1: content type "text/plain" is not declared, want one of [application/json]`},
+		"body":   {"openapi: response 200 for GET /users/1: body/name is required; body/extra is not allowed; body/id must be greater than or equal to 1"},
+	} {
+		t.Run(query, func(t *testing.T) {
+			r := &recorder{}
+			req := httptest.NewRequest("GET", "/users/1?case="+query, nil)
+			rec := httptest.NewRecorder()
+			Handler(r, doc, handler).ServeHTTP(rec, req)
+			require.Equal(t, want, r.errors)
+			require.Equal(t, len(want) == 0, CheckResponse(r, doc, req, rec))
+		})
+	}
+}
+
+func TestCheckResponseHeaders(t *testing.T) {
+	doc := openapi.NewDocument("users", "1.0.0")
+	helper := doc.Register(userRoute{})
+	helper.Response["200"].Headers = map[string]*models.Header{
+		"X-Rate-Limit": {Required: true, Schema: &models.Schema{Type: "integer"}},
+	}
+	r := &recorder{}
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	_, _ = rec.Write([]byte(`{"id":1,"name":"a"}`))
+	require.False(t, CheckResponse(r, doc, req, rec))
+	require.Equal(t, []string{"openapi: response 200 for GET /users/1: header X-Rate-Limit is required"}, r.errors)
+
+	r = &recorder{}
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.Header().Set("X-Rate-Limit", "many")
+	_, _ = rec.Write([]byte(`{"id":1,"name":"a"}`))
+	require.False(t, CheckResponse(r, doc, req, rec))
+	require.Equal(t, []string{`openapi: response 200 for GET /users/1: header X-Rate-Limit must be integer, got "many"`}, r.errors)
+}
+
+// sharedComponents components中的参数,头和请求body,required和参数的顺序是乱的,golden文件中应该已经排序
+const sharedComponents = `{
+  "openapi": "3.0.2",
+  "paths": {
+    "/users": {
+      "parameters": [
+        {"name": "sort", "in": "query", "schema": {"type": "string"}},
+        {"name": "X-Tenant", "in": "header", "schema": {"type": "string"}}
+      ]
+    }
+  },
+  "components": {
+    "parameters": {
+      "filter": {"name": "filter", "in": "query", "schema": {"type": "object", "required": ["to", "from"]}}
+    },
+    "headers": {
+      "X-Meta": {"schema": {"type": "object", "required": ["to", "from"]}}
+    },
+    "requestBodies": {
+      "Range": {"content": {"application/json": {"schema": {"type": "object", "required": ["to", "from", "to"]}}}}
+    }
+  }
+}`
+
+func TestGolden(t *testing.T) {
+	doc := openapi.NewDocument("users", "1.0.0")
+	doc.Register(userRoute{})
+	spec, err := openapi.Load(strings.NewReader(sharedComponents))
+	require.NoError(t, err)
+	require.NoError(t, doc.Merge(spec))
+	for _, path := range []string{"testdata/users.json", "testdata/users.yaml"} {
+		require.True(t, Golden(t, doc, path), path)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	doc.Apply(openapi.WithDescription("changed"))
+	r := &recorder{}
+	require.False(t, Golden(r, doc, "testdata/users.yaml"))
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "run with OPENAPI_UPDATE_GOLDEN=1 to update it")
+
+	r = &recorder{}
+	require.False(t, Golden(r, doc, "testdata/missing.json"))
+	require.Contains(t, r.errors[0], "to create it")
+}
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OperationIDNamer 为没有实现RouteOperationID的路由生成operationId,重复的id会自动加上数字后缀
+type OperationIDNamer func(method, path string, route RouteStruct) string
+
+// DefaultOperationID 用method和path生成驼峰形式的operationId,
+// 例如GET /users/{id}/orders生成getUsersByIdOrders
+func DefaultOperationID(method, path string, route RouteStruct) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			b.WriteString("By")
+			segment = segment[1 : len(segment)-1]
+		}
+		b.WriteString(camelCase(segment))
+	}
+	return b.String()
+}
+
+// camelCase 去掉非字母数字的字符,并把每个单词的首字母大写
+func camelCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// operationID 返回路由的operationId,路由自己指定的id和其他operation重复时返回错误
+func (n *Document) operationID(method, path string, route RouteStruct) (string, error) {
+	key := method + " " + path
+	if r, ok := route.(RouteOperationID); ok && r.GetOperationID() != "" {
+		id := r.GetOperationID()
+		if owner, ok := n.operationIDs[id]; ok && owner != key {
+			return "", fmt.Errorf("openapi: operationId %q of %s is already used by %s", id, key, owner)
+		}
+		return id, nil
+	}
+	namer := n.OperationIDNamer
+	if namer == nil {
+		namer = DefaultOperationID
+	}
+	base := namer(method, path, route)
+	id := base
+	for i := 2; ; i++ {
+		if owner, ok := n.operationIDs[id]; !ok || owner == key {
+			return id, nil
+		}
+		id = base + strconv.Itoa(i)
+	}
+}
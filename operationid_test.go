@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultOperationID(t *testing.T) {
+	require.Equal(t, "getUsersByIdOrders", DefaultOperationID("GET", "/users/{id}/orders", nil))
+	require.Equal(t, "postUserProfiles", DefaultOperationID("POST", "/user-profiles/", nil))
+	require.Equal(t, "get", DefaultOperationID("GET", "/", nil))
+}
+
+func TestDocumentOperationIDs(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.OperationIDNamer = func(method, path string, route RouteStruct) string {
+		return "handler"
+	}
+	// 没有body的路由
+	doc.Register(&TestRouter{Method: "GET", Path: "/a"})
+	doc.Register(&TestRouter{Method: "GET", Path: "/b"})
+	doc.Register(&TestRouter{Method: "GET", Path: "/a"})
+	spec := doc.Snapshot()
+	require.Equal(t, "handler", spec.Paths["/a"].Get.OperationId)
+	require.Equal(t, "handler2", spec.Paths["/b"].Get.OperationId)
+
+	_, err := doc.RegisterE(&MetaRouter{TestRouter: TestRouter{Method: "POST", Path: "/c"}, OperationID: "handler2"})
+	require.EqualError(t, err, `openapi: operationId "handler2" of POST /c is already used by GET /b`)
+	_, err = doc.RegisterE(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/b"}, OperationID: "handler2"})
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"github.com/Chise1/openapi/models"
+)
+
+// Option 设置文档的元数据,用于NewDocument和Document.Apply
+type Option func(spec *models.OpenAPI)
+
+// WithInfo 替换整个Info,title和version为空时保留原来的值
+func WithInfo(info models.Info) Option {
+	return func(spec *models.OpenAPI) {
+		if info.Title == "" {
+			info.Title = spec.Info.Title
+		}
+		if info.Version == "" {
+			info.Version = spec.Info.Version
+		}
+		spec.Info = &info
+	}
+}
+
+// WithDescription 设置文档描述,支持CommonMark
+func WithDescription(description string) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Info.Description = description
+	}
+}
+
+// WithTermsOfService 设置服务条款的url
+func WithTermsOfService(url string) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Info.TermsOfService = url
+	}
+}
+
+// WithContact 设置联系人信息
+func WithContact(name, url, email string) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Info.Contact = &models.Contact{
+			Name:  name,
+			Url:   url,
+			Email: email,
+		}
+	}
+}
+
+// WithLicense 设置许可证
+func WithLicense(name, url string) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Info.License = &models.License{
+			Name: name,
+			Url:  url,
+		}
+	}
+}
+
+// WithServer 添加一个服务器地址,url中可以用{name}引用vars中的变量
+func WithServer(url, description string, vars map[string]models.ServerVariable) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Servers = append(spec.Servers, &models.Server{
+			Url:         url,
+			Description: description,
+			Variables:   vars,
+		})
+	}
+}
+
+// WithTag 添加一个标签的说明,同名的标签会被替换,docs可以为nil
+func WithTag(name, description string, docs *models.ExternalDocumentation) Option {
+	return func(spec *models.OpenAPI) {
+		tag := &models.Tag{
+			Name:         name,
+			Description:  description,
+			ExternalDocs: docs,
+		}
+		for i := range spec.Tags {
+			if spec.Tags[i].Name == name {
+				spec.Tags[i] = tag
+				return
+			}
+		}
+		spec.Tags = append(spec.Tags, tag)
+	}
+}
+
+// WithExternalDocs 设置文档的外部链接
+func WithExternalDocs(url, description string) Option {
+	return func(spec *models.OpenAPI) {
+		spec.ExternalDocs = &models.ExternalDocumentation{
+			Url:         url,
+			Description: description,
+		}
+	}
+}
+
+// Apply 修改文档的元数据
+func (n *Document) Apply(opts ...Option) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// 快照共享了Info,先复制一份再修改
+	info := *n.spec.Info
+	n.spec.Info = &info
+	for _, opt := range opts {
+		opt(n.spec)
+	}
+	n.snapshot = nil
+}
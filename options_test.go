@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentOptions(t *testing.T) {
+	doc := NewDocument("billing", "1.2.0",
+		WithDescription("Billing service"),
+		WithContact("Billing team", "https://example.com/billing", "billing@example.com"),
+		WithLicense("MIT", "https://opensource.org/licenses/MIT"),
+		WithServer("https://{region}.example.com/v1", "production", map[string]models.ServerVariable{
+			"region": {Default: "eu", Enum: []string{"eu", "us"}},
+		}),
+		WithTag("invoices", "Invoice operations", nil),
+		WithTag("invoices", "Invoices", &models.ExternalDocumentation{Url: "https://example.com/docs/invoices"}),
+		WithExternalDocs("https://example.com/docs", ""),
+	)
+	snapshot := doc.Snapshot()
+	doc.Apply(WithInfo(models.Info{Title: "billing-api"}))
+
+	b, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"openapi": "3.0.2",
+		"info": {
+			"title": "billing",
+			"description": "Billing service",
+			"contact": {"name": "Billing team", "url": "https://example.com/billing", "email": "billing@example.com"},
+			"license": {"name": "MIT", "url": "https://opensource.org/licenses/MIT"},
+			"version": "1.2.0"
+		},
+		"servers": [{
+			"url": "https://{region}.example.com/v1",
+			"description": "production",
+			"variables": {"region": {"enum": ["eu", "us"], "default": "eu"}}
+		}],
+		"components": {},
+		"tags": [{"name": "invoices", "description": "Invoices", "externalDocs": {"url": "https://example.com/docs/invoices"}}],
+		"externalDocs": {"url": "https://example.com/docs"}
+	}`, string(b))
+
+	info := doc.Snapshot().Info
+	require.Equal(t, "billing-api", info.Title)
+	require.Equal(t, "1.2.0", info.Version)
+	require.Nil(t, info.Contact)
+}
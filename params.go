@@ -0,0 +1,300 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// paramSource 请求中各个位置的参数
+type paramSource struct {
+	r     *http.Request
+	query url.Values
+	vars  map[string]string
+}
+
+func newParamSource(r *http.Request, vars map[string]string) *paramSource {
+	return &paramSource{r: r, query: r.URL.Query(), vars: vars}
+}
+
+// paramStyle 返回参数的style和explode,没有设置时使用规范中的默认值
+func paramStyle(param *models.Parameter) (string, bool) {
+	style := param.Style
+	if style == "" {
+		switch param.In {
+		case "query", "cookie":
+			style = "form"
+		default:
+			style = "simple"
+		}
+	}
+	explode := style == "form"
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return style, explode
+}
+
+// value 按参数的style和explode取出参数的值,并按schema转换为json的值,参数不存在时返回false
+func (n *paramSource) value(schemas map[string]*models.Schema, param *models.Parameter) (interface{}, bool, error) {
+	schema := resolveSchema(schemas, param.Schema)
+	style, explode := paramStyle(param)
+	switch param.In {
+	case "path":
+		v, ok := n.vars[param.Name]
+		if !ok {
+			return nil, false, nil
+		}
+		value, err := pathValue(schemas, schema, param.Name, style, explode, v)
+		return value, true, err
+	case "query":
+		return n.queryValue(schemas, schema, param.Name, style, explode)
+	case "header":
+		v := n.r.Header.Values(param.Name)
+		if len(v) == 0 {
+			return nil, false, nil
+		}
+		value, err := splitValue(schemas, schema, strings.Join(v, ","), ",", explode)
+		return value, true, err
+	case "cookie":
+		c, err := n.r.Cookie(param.Name)
+		if err != nil {
+			return nil, false, nil
+		}
+		value, err := splitValue(schemas, schema, c.Value, ",", false)
+		return value, true, err
+	}
+	return nil, false, nil
+}
+
+func (n *paramSource) queryValue(schemas map[string]*models.Schema, schema *models.Schema, name, style string, explode bool) (interface{}, bool, error) {
+	typ := schemaType(schema)
+	if style == "deepObject" || (typ == "object" && style == "form" && explode) {
+		// deepObject为name[key]=value,form展开的对象每个属性都是单独的参数
+		fields := map[string]string{}
+		if style == "deepObject" {
+			prefix := name + "["
+			for key, vs := range n.query {
+				if strings.HasPrefix(key, prefix) && strings.HasSuffix(key, "]") {
+					fields[key[len(prefix):len(key)-1]] = vs[0]
+				}
+			}
+		} else if schema.Properties != nil {
+			for _, key := range schema.Properties.Keys() {
+				if vs, ok := n.query[key]; ok {
+					fields[key] = vs[0]
+				}
+			}
+		}
+		if len(fields) == 0 {
+			return nil, false, nil
+		}
+		value, err := coerceObject(schemas, schema, fields)
+		return value, true, err
+	}
+	vs, ok := n.query[name]
+	if !ok {
+		return nil, false, nil
+	}
+	if typ == "array" && explode {
+		value, err := coerceArray(schemas, schema, vs)
+		return value, true, err
+	}
+	sep := ","
+	switch style {
+	case "spaceDelimited":
+		sep = " "
+	case "pipeDelimited":
+		sep = "|"
+	}
+	value, err := splitValue(schemas, schema, vs[0], sep, false)
+	return value, true, err
+}
+
+// pathValue 按simple,label或者matrix解析path参数
+func pathValue(schemas map[string]*models.Schema, schema *models.Schema, name, style string, explode bool, v string) (interface{}, error) {
+	switch style {
+	case "label":
+		if !strings.HasPrefix(v, ".") {
+			return nil, fmt.Errorf("must start with %q", ".")
+		}
+		sep := ","
+		if explode {
+			sep = "."
+		}
+		return splitValue(schemas, schema, v[1:], sep, explode)
+	case "matrix":
+		if !strings.HasPrefix(v, ";") {
+			return nil, fmt.Errorf("must start with %q", ";")
+		}
+		typ := schemaType(schema)
+		if explode && typ == "object" {
+			return splitValue(schemas, schema, v[1:], ";", true)
+		}
+		prefix := name + "="
+		if explode && typ == "array" {
+			parts := strings.Split(v[1:], ";")
+			for i, part := range parts {
+				if !strings.HasPrefix(part, prefix) {
+					return nil, fmt.Errorf("must be in the form ;%s=value", name)
+				}
+				parts[i] = part[len(prefix):]
+			}
+			return coerceArray(schemas, schema, parts)
+		}
+		if !strings.HasPrefix(v[1:], prefix) {
+			return nil, fmt.Errorf("must be in the form ;%s=value", name)
+		}
+		return splitValue(schemas, schema, v[1+len(prefix):], ",", false)
+	}
+	return splitValue(schemas, schema, v, ",", explode)
+}
+
+// splitValue 用sep分割数组或者对象,explode的对象为key=value,否则为key,value交替出现
+func splitValue(schemas map[string]*models.Schema, schema *models.Schema, v, sep string, explode bool) (interface{}, error) {
+	switch schemaType(schema) {
+	case "array":
+		return coerceArray(schemas, schema, strings.Split(v, sep))
+	case "object":
+		parts := strings.Split(v, sep)
+		fields := map[string]string{}
+		if explode {
+			for _, part := range parts {
+				i := strings.IndexByte(part, '=')
+				if i < 0 {
+					return nil, fmt.Errorf("must be key=value pairs separated by %q", sep)
+				}
+				fields[part[:i]] = part[i+1:]
+			}
+		} else {
+			if len(parts)%2 != 0 {
+				return nil, fmt.Errorf("must be key and value pairs separated by %q", sep)
+			}
+			for i := 0; i < len(parts); i += 2 {
+				fields[parts[i]] = parts[i+1]
+			}
+		}
+		return coerceObject(schemas, schema, fields)
+	}
+	return coerceValue(schemas, schema, v)
+}
+
+func schemaType(schema *models.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	return schema.Type
+}
+
+// resolveSchema 解析$ref,并去掉nullable生成的oneOf
+func resolveSchema(schemas map[string]*models.Schema, schema *models.Schema) *models.Schema {
+	for i := 0; schema != nil && i < maxRefDepth; i++ {
+		if schema.Ref != "" {
+			schema = schemas[strings.TrimPrefix(schema.Ref, models.REF_PREFIX)]
+			continue
+		}
+		if schema.Type == "" && len(schema.OneOf) == 2 && schema.OneOf[1].Type == "null" {
+			schema = schema.OneOf[0]
+			continue
+		}
+		break
+	}
+	return schema
+}
+
+const maxRefDepth = 32
+
+// coerceParam 按schema把simple风格的参数值转换为json的值,多个值或者逗号分隔的值转换为数组
+func coerceParam(schemas map[string]*models.Schema, schema *models.Schema, values []string) (interface{}, error) {
+	schema = resolveSchema(schemas, schema)
+	if schema == nil || len(values) == 0 {
+		return nil, nil
+	}
+	if schema.Type == "array" && len(values) > 1 {
+		return coerceArray(schemas, schema, values)
+	}
+	return splitValue(schemas, schema, values[0], ",", false)
+}
+
+func coerceArray(schemas map[string]*models.Schema, schema *models.Schema, values []string) (interface{}, error) {
+	items := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		item, err := coerceValue(schemas, schema.Items, v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func coerceObject(schemas map[string]*models.Schema, schema *models.Schema, fields map[string]string) (interface{}, error) {
+	object := make(map[string]interface{}, len(fields))
+	for key, v := range fields {
+		value, err := coerceValue(schemas, propertySchema(schema, key), v)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", key, err)
+		}
+		object[key] = value
+	}
+	return object, nil
+}
+
+// propertySchema 返回对象中key对应的schema,依次查找properties和patternProperties
+func propertySchema(schema *models.Schema, key string) *models.Schema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Properties != nil {
+		if v, ok := schema.Properties.Get(key); ok {
+			property, _ := v.(*models.Schema)
+			return property
+		}
+	}
+	for pattern, property := range schema.PatternProperties {
+		if ok, _ := regexp.MatchString(pattern, key); ok {
+			return property
+		}
+	}
+	return nil
+}
+
+var (
+	integerPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	numberPattern  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+func coerceValue(schemas map[string]*models.Schema, schema *models.Schema, v string) (interface{}, error) {
+	schema = resolveSchema(schemas, schema)
+	if schema == nil {
+		return v, nil
+	}
+	switch schema.Type {
+	case "integer", "number":
+		pattern := numberPattern
+		if schema.Type == "integer" {
+			pattern = integerPattern
+		}
+		// ParseFloat还接受NaN,Inf,1_000,0x10这些json中不合法的写法
+		if !pattern.MatchString(v) {
+			return nil, fmt.Errorf("must be %s, got %q", schema.Type, v)
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("must be %s, got %q", schema.Type, v)
+		}
+		return json.Number(v), nil
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("must be boolean, got %q", v)
+		}
+		return b, nil
+	}
+	return v, nil
+}
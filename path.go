@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pathTemplate 解析后的path模板
+type pathTemplate struct {
+	path     string   //openapi形式的模板,例如/users/{id}
+	params   []string //模板中的参数名,按出现的顺序
+	catchAll bool     //最后一个参数由*rest转换而来,匹配剩余的整个路径
+}
+
+// ConvertPath 把gin,httprouter等路由的写法转换为openapi的path模板,
+// 例如/users/:id/*rest转换为/users/{id}/{rest}
+func ConvertPath(path string) (string, error) {
+	t, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+	return t.path, nil
+}
+
+// parsePath 解析path模板,支持{id},:id和*rest三种参数写法.
+// 括号不匹配,参数名为空或者重复,*rest不在最后一段时返回错误
+func parsePath(path string) (*pathTemplate, error) {
+	t := &pathTemplate{}
+	seen := map[string]bool{}
+	add := func(name string) error {
+		if name == "" {
+			return fmt.Errorf("openapi: path %q has an empty parameter name", path)
+		}
+		if seen[name] {
+			return fmt.Errorf("openapi: path %q has duplicate parameter %q", path, name)
+		}
+		seen[name] = true
+		t.params = append(t.params, name)
+		return nil
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			if err := add(segment[1:]); err != nil {
+				return nil, err
+			}
+			segments[i] = "{" + segment[1:] + "}"
+			continue
+		case strings.HasPrefix(segment, "*"):
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("openapi: catch-all parameter %q must be the last segment of path %q", segment, path)
+			}
+			if err := add(segment[1:]); err != nil {
+				return nil, err
+			}
+			segments[i] = "{" + segment[1:] + "}"
+			t.catchAll = true
+			continue
+		}
+		for rest := segment; rest != ""; {
+			start := strings.IndexAny(rest, "{}")
+			if start < 0 {
+				break
+			}
+			if rest[start] == '}' {
+				return nil, fmt.Errorf("openapi: path %q has unbalanced braces", path)
+			}
+			end := strings.IndexAny(rest[start+1:], "{}")
+			if end < 0 || rest[start+1+end] == '{' {
+				return nil, fmt.Errorf("openapi: path %q has unbalanced braces", path)
+			}
+			if err := add(rest[start+1 : start+1+end]); err != nil {
+				return nil, err
+			}
+			rest = rest[start+end+2:]
+		}
+	}
+	t.path = strings.Join(segments, "/")
+	return t, nil
+}
+
+// checkPathParams 检查模板中的参数和in:"path"的字段是否一一对应,并把path参数标记为必须
+func checkPathParams(t *pathTemplate, params Parameters) error {
+	fields := map[string]bool{}
+	for _, param := range params {
+		if param.In != "path" {
+			continue
+		}
+		param.Required = true
+		fields[param.Name] = true
+	}
+	for _, name := range t.params {
+		if !fields[name] {
+			return fmt.Errorf("path parameter {%s} has no field with in:\"path\"", name)
+		}
+		delete(fields, name)
+	}
+	for _, param := range params {
+		if fields[param.Name] && param.In == "path" {
+			return fmt.Errorf("parameter %q has in:\"path\" but {%s} is not in the path", param.Name, param.Name)
+		}
+	}
+	return nil
+}
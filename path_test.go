@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type PathID struct {
+	ID string `json:"id" in:"path"`
+}
+
+type PathName struct {
+	Name string `json:"name" in:"path"`
+}
+
+type PathFile struct {
+	Name string `json:"name" in:"path"`
+	Ext  string `json:"ext" in:"path"`
+}
+
+type OptionalPathID struct {
+	ID    string `json:"id,omitempty" in:"path"`
+	Query string `json:"q,omitempty"`
+}
+
+func TestParsePath(t *testing.T) {
+	for path, want := range map[string]pathTemplate{
+		"/users":               {path: "/users"},
+		"/users/{id}":          {path: "/users/{id}", params: []string{"id"}},
+		"/users/:id/orders":    {path: "/users/{id}/orders", params: []string{"id"}},
+		"/files/{name}.{ext}":  {path: "/files/{name}.{ext}", params: []string{"name", "ext"}},
+		"/static/:dir/*path":   {path: "/static/{dir}/{path}", params: []string{"dir", "path"}, catchAll: true},
+		"/v1/users/{id}/:role": {path: "/v1/users/{id}/{role}", params: []string{"id", "role"}},
+	} {
+		got, err := parsePath(path)
+		require.NoError(t, err, path)
+		require.Equal(t, want, *got, path)
+	}
+	for path, msg := range map[string]string{
+		"/users/{id":        `openapi: path "/users/{id" has unbalanced braces`,
+		"/users/id}":        `openapi: path "/users/id}" has unbalanced braces`,
+		"/users/{{id}}":     `openapi: path "/users/{{id}}" has unbalanced braces`,
+		"/users/{}":         `openapi: path "/users/{}" has an empty parameter name`,
+		"/users/:":          `openapi: path "/users/:" has an empty parameter name`,
+		"/users/{id}/:id":   `openapi: path "/users/{id}/:id" has duplicate parameter "id"`,
+		"/static/*path/raw": `openapi: catch-all parameter "*path" must be the last segment of path "/static/*path/raw"`,
+	} {
+		_, err := parsePath(path)
+		require.EqualError(t, err, msg, path)
+	}
+	path, err := ConvertPath("/users/:id")
+	require.NoError(t, err)
+	require.Equal(t, "/users/{id}", path)
+}
+
+func TestRegisterPathParams(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	_, err := doc.RegisterE(&TestRouter{Method: "GET", Path: "/users/{id}"})
+	require.EqualError(t, err, `openapi: GET /users/{id}: path parameter {id} has no field with in:"path"`)
+	_, err = doc.RegisterE(&TestRouter{Method: "GET", Path: "/users", Param: PathID{}})
+	require.EqualError(t, err, `openapi: GET /users: parameter "id" has in:"path" but {id} is not in the path`)
+	_, err = doc.RegisterE(&TestRouter{Method: "GET", Path: "/users/{id"})
+	require.EqualError(t, err, `openapi: path "/users/{id" has unbalanced braces`)
+	require.Empty(t, doc.Snapshot().Paths)
+
+	doc.Register(&TestRouter{Method: "GET", Path: "/users/:id", Param: OptionalPathID{}})
+	params := doc.Snapshot().Paths["/users/{id}"].Get.Parameters
+	require.Equal(t, "id", params[0].Name)
+	require.True(t, params[0].Required)
+	require.False(t, params[1].Required)
+}
+
+func TestRouterCatchAll(t *testing.T) {
+	router := NewRouter(NewDocument("test", "1.0.0"), nil)
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/static/:dir/*path", Param: StaticPara{}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			p := para.(*StaticPara)
+			return nil, ReqStruct{Hello: p.Dir + ":" + p.Path}
+		}))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/static/css/a/b%20c.css", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"css:a/b c.css"}`, rec.Body.String())
+	require.Contains(t, router.Document().Snapshot().Paths, "/static/{dir}/{path}")
+}
+
+func TestFindOperationCatchAll(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&MetaRouter{TestRouter: TestRouter{Method: "GET", Path: "/static/:dir/*path", Param: StaticPara{}}, OperationID: "getStatic"})
+	oper, vars, ok := doc.FindOperation("GET", "/static/css/a/b%20c.css")
+	require.True(t, ok)
+	require.Equal(t, "getStatic", oper.OperationId)
+	require.Equal(t, map[string]string{"dir": "css", "path": "a/b c.css"}, vars)
+
+	called := false
+	handler := doc.ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/static/css/a/b.css", nil))
+	require.True(t, called)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, doc.ValidateResponse(httptest.NewRequest("GET", "/static/css/a/b.css", nil), http.StatusOK,
+		http.Header{"Content-Type": {"application/json"}}, []byte(`{"hello":"world"}`)))
+
+	_, err := doc.RegisterE(&TestRouter{Method: "POST", Path: "/static/{dir}/{path}", Param: StaticPara{}})
+	require.EqualError(t, err, "openapi: POST /static/{dir}/{path}: catch-all parameter does not match the path already registered")
+	_, _, ok = doc.FindOperation("POST", "/static/css/a/b.css")
+	require.False(t, ok)
+}
+
+type StaticPara struct {
+	Dir  string `json:"dir" in:"path"`
+	Path string `json:"path" in:"path"`
+}
@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Problem RFC 7807格式的错误响应
+type Problem struct {
+	Type          string         `json:"type,omitempty"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam 校验失败的一个参数
+type InvalidParam struct {
+	Name   string `json:"name"` //参数名,body中的值为JSON pointer
+	In     string `json:"in"`   //path,query,header,cookie或body
+	Reason string `json:"reason"`
+}
+
+// NewProblem 创建Problem,title使用状态码对应的文本
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func (n *Problem) Error() string {
+	msg := strconv.Itoa(n.Status) + " " + n.Title
+	if n.Detail != "" {
+		msg += ": " + n.Detail
+	}
+	for _, p := range n.InvalidParams {
+		msg += "; " + p.In + " " + p.Name + ": " + p.Reason
+	}
+	return msg
+}
+
+// ServeHTTP 以application/problem+json格式写出错误
+func (n *Problem) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(n.Status)
+	_, _ = w.Write(b)
+}
@@ -10,6 +10,7 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/Chise1/openapi/models"
 	"github.com/iancoleman/orderedmap"
 	"math"
@@ -62,6 +63,13 @@ func ReflectFromType(t reflect.Type) *SchemaChild {
 	return r.ReflectFromType(t)
 }
 
+// ReflectE reflects to SchemaChild from a value using the default Reflector,
+// returning an error for types that can not be reflected.
+func ReflectE(v interface{}) (*SchemaChild, error) {
+	r := &Reflector{}
+	return r.ReflectE(v)
+}
+
 // A Reflector reflects values into a SchemaChild.
 type Reflector struct {
 	// AllowAdditionalProperties will cause the Reflector to generate a schema
@@ -116,15 +124,48 @@ type Reflector struct {
 
 	// AdditionalFields allows adding structfields for a given type
 	AdditionalFields func(reflect.Type) []reflect.StructField
+
+	// IgnoreUnsupportedFields will cause struct fields whose type has no schema
+	// representation (channels, funcs, complex numbers, unsafe pointers) to be
+	// left out instead of failing the reflection.
+	IgnoreUnsupportedFields bool
+
+	// namer resolves component name collisions while registering into a Document.
+	namer *componentNamer
 }
 
 // Reflect reflects to SchemaChild from a value.
+// It panics if the value contains a type that can not be reflected.
 func (n *Reflector) Reflect(v interface{}) *SchemaChild {
 	return n.ReflectFromType(reflect.TypeOf(v))
 }
 
+// ReflectE is like Reflect but returns an *UnsupportedTypeError instead of panicking.
+// Other panics during reflection, for example in TypeMapper, are returned as errors too.
+func (n *Reflector) ReflectE(v interface{}) (*SchemaChild, error) {
+	return n.ReflectFromTypeE(reflect.TypeOf(v))
+}
+
 // ReflectFromType generates root schema
+// It panics if t contains a type that can not be reflected.
 func (n *Reflector) ReflectFromType(t reflect.Type) *SchemaChild {
+	s, err := n.ReflectFromTypeE(t)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ReflectFromTypeE is like ReflectFromType but returns an *UnsupportedTypeError
+// instead of panicking.
+func (n *Reflector) ReflectFromTypeE(t reflect.Type) (s *SchemaChild, err error) {
+	err = catchUnsupported(t, func() {
+		s = n.reflectFromType(t)
+	})
+	return s, err
+}
+
+func (n *Reflector) reflectFromType(t reflect.Type) *SchemaChild {
 	components := Definitions{}
 	if n.ExpandedStruct {
 		st := &models.Schema{
@@ -138,7 +179,7 @@ func (n *Reflector) ReflectFromType(t reflect.Type) *SchemaChild {
 		}
 		n.reflectStructFields(st, components, t)
 		n.reflectStruct(components, t)
-		delete(components, n.TypeName(t))
+		delete(components, n.definitionName(t))
 		return &SchemaChild{Schema: st, Components: components}
 	}
 
@@ -178,8 +219,8 @@ var protoEnumType = reflect.TypeOf((*protoEnum)(nil)).Elem()
 
 func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type) *models.Schema {
 	// Already added to definitions?
-	if _, ok := definitions[n.TypeName(t)]; ok && !n.DoNotReference {
-		return &models.Schema{Ref: models.REF_PREFIX + n.TypeName(t)}
+	if name, ok := n.definedName(definitions, t); ok && !n.DoNotReference {
+		return &models.Schema{Ref: models.REF_PREFIX + name}
 	}
 
 	if n.TypeMapper != nil {
@@ -204,6 +245,9 @@ func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 	// Defined format types for JSON SchemaChild Validation
 	// RFC draft-wright-json-schema-validation-00, section 7.3
 	// TODO email RFC section 7.3.2, hostname RFC section 7.3.3, uriref RFC section 7.3.7
+	if isFileType(t) {
+		return &models.Schema{Type: "string", Format: "binary"}
+	}
 	if t == ipType {
 		// TODO differentiate ipv4 and ipv6 RFC section 7.3.4, 7.3.5
 		return &models.Schema{Type: "string", Format: "ipv4", Title: n.TypeName(t)} // ipv4 RFC section 7.3.4
@@ -226,7 +270,7 @@ func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 			rt := &models.Schema{
 				Type: "object",
 				PatternProperties: map[string]*models.Schema{
-					"^[0-9]+$": n.reflectTypeToSchema(definitions, t.Elem()),
+					"^[0-9]+$": n.reflectElem(definitions, t.Elem(), "{}"),
 				},
 				AdditionalProperties: []byte("false"),
 			}
@@ -236,7 +280,7 @@ func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 		rt := &models.Schema{
 			Type: "object",
 			PatternProperties: map[string]*models.Schema{
-				".*": n.reflectTypeToSchema(definitions, t.Elem()),
+				".*": n.reflectElem(definitions, t.Elem(), "{}"),
 			},
 		}
 		delete(rt.PatternProperties, "additionalProperties")
@@ -259,7 +303,7 @@ func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 			return returnType
 		}
 		returnType.Type = "array"
-		returnType.Items = n.reflectTypeToSchema(definitions, t.Elem())
+		returnType.Items = n.reflectElem(definitions, t.Elem(), "[]")
 		return returnType
 
 	case reflect.Interface:
@@ -313,7 +357,14 @@ func (n *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 	case reflect.Ptr:
 		return n.reflectTypeToSchema(definitions, t.Elem())
 	}
-	panic("unsupported type " + t.String())
+	panic(&UnsupportedTypeError{Type: t})
+}
+
+// reflectElem reflects the element type of a container, prefixing segment to
+// the path of any unsupported type found inside it.
+func (n *Reflector) reflectElem(definitions Definitions, t reflect.Type, segment string) *models.Schema {
+	defer prefixPath(segment)
+	return n.reflectTypeToSchema(definitions, t)
 }
 
 func (n *Reflector) reflectCustomType(definitions Definitions, t reflect.Type) *models.Schema {
@@ -325,13 +376,14 @@ func (n *Reflector) reflectCustomType(definitions Definitions, t reflect.Type) *
 		v := reflect.New(t)
 		o := v.Interface().(customSchemaType)
 		st := o.JSONSchemaType()
-		definitions[n.TypeName(t)] = st
+		name := n.definitionName(t)
+		definitions[name] = st
 		if n.DoNotReference {
 			return st
 		} else {
 			return &models.Schema{
 				Version: Version,
-				Ref:     models.REF_PREFIX + n.TypeName(t),
+				Ref:     models.REF_PREFIX + name,
 			}
 		}
 	}
@@ -352,14 +404,15 @@ func (n *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *mode
 				Properties:           orderedmap.New(),
 				AdditionalProperties: []byte("true"),
 			}
-			definitions[n.TypeName(t)] = st
+			name := n.definitionName(t)
+			definitions[name] = st
 
 			if n.DoNotReference {
 				return st
 			} else {
 				return &models.Schema{
 					Version: Version,
-					Ref:     models.REF_PREFIX + n.TypeName(t),
+					Ref:     models.REF_PREFIX + name,
 				}
 			}
 		}
@@ -374,7 +427,8 @@ func (n *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *mode
 	if n.AllowAdditionalProperties {
 		st.AdditionalProperties = []byte("true")
 	}
-	definitions[n.TypeName(t)] = st
+	name := n.definitionName(t)
+	definitions[name] = st
 	n.reflectStructFields(st, definitions, t)
 
 	if n.DoNotReference {
@@ -382,7 +436,7 @@ func (n *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *mode
 	} else {
 		return &models.Schema{
 			Version: Version,
-			Ref:     models.REF_PREFIX + n.TypeName(t),
+			Ref:     models.REF_PREFIX + name,
 		}
 	}
 }
@@ -404,6 +458,10 @@ func (n *Reflector) reflectStructFields(st *models.Schema, definitions Definitio
 
 	handleField := func(f reflect.StructField) {
 		name, shouldEmbed, required, nullable := n.reflectFieldName(f)
+		if name != "" && n.IgnoreUnsupportedFields && !n.supported(f.Type) {
+			return
+		}
+		defer prefixPath("." + f.Name)
 		// if anonymous and exported type should be processed recursively
 		// current type should inherit properties of anonymous one
 		if name == "" {
@@ -561,6 +619,89 @@ func (n *Reflector) reflectFieldName(f reflect.StructField) (string, bool, bool,
 	return name, embed, required, nullable
 }
 
+// UnsupportedTypeError is returned when a Go type has no schema representation.
+// Path is the location of the type inside the reflected value, for example
+// Order.Items[].Price.
+type UnsupportedTypeError struct {
+	Path string
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return e.Path + ": unsupported type " + e.Type.String()
+}
+
+// reflectPanic wraps any other value panicking through the reflection walk,
+// such as a panic in a TypeMapper, so that its path can be reported too.
+type reflectPanic struct {
+	path  string
+	value interface{}
+}
+
+// prefixPath adds segment to the path of an *UnsupportedTypeError or any other
+// value panicking through the caller. It must be deferred directly.
+func prefixPath(segment string) {
+	if r := recover(); r != nil {
+		switch e := r.(type) {
+		case *UnsupportedTypeError:
+			e.Path = segment + e.Path
+		case *reflectPanic:
+			e.path = segment + e.path
+		default:
+			r = &reflectPanic{path: segment, value: r}
+		}
+		panic(r)
+	}
+}
+
+// catchUnsupported runs f and returns the *UnsupportedTypeError it panics with,
+// rooting the error path at the name of t. Any other panic is returned as an
+// error as well, so the *E functions never panic.
+func catchUnsupported(t reflect.Type, f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			root := t.Name()
+			if root == "" {
+				root = t.String()
+			}
+			switch e := r.(type) {
+			case *UnsupportedTypeError:
+				e.Path = root + e.Path
+				err = e
+			case *reflectPanic:
+				err = fmt.Errorf("%s%s: %v", root, e.path, e.value)
+			default:
+				err = fmt.Errorf("%s: %v", root, r)
+			}
+		}
+	}()
+	f()
+	return nil
+}
+
+// supported reports whether t has a schema representation.
+func (n *Reflector) supported(t reflect.Type) bool {
+	for {
+		if n.TypeMapper != nil && n.TypeMapper(t) != nil {
+			return true
+		}
+		if t.Implements(customType) || reflect.PtrTo(t).Implements(customType) {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer, reflect.Uintptr:
+			return false
+		default:
+			return true
+		}
+	}
+}
+
 func (n *SchemaChild) MarshalJSON() ([]byte, error) {
 	b, err := json.Marshal(n.Schema)
 	if err != nil {
@@ -583,6 +724,48 @@ func (n *SchemaChild) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// UnmarshalJSON 解析根schema和components,components不会出现在Schema.Extras中
+func (n *SchemaChild) UnmarshalJSON(b []byte) error {
+	var components struct {
+		Components Definitions `json:"components,omitempty"`
+	}
+	if err := json.Unmarshal(b, &components); err != nil {
+		return err
+	}
+	schema := &models.Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		return err
+	}
+	delete(schema.Extras, "components")
+	if len(schema.Extras) == 0 {
+		schema.Extras = nil
+	}
+	n.Schema = schema
+	n.Components = components.Components
+	return nil
+}
+
+// definitionName returns the name t is stored under in the definitions.
+func (n *Reflector) definitionName(t reflect.Type) string {
+	if n.namer == nil {
+		return n.TypeName(t)
+	}
+	return n.namer.claim(n.TypeName(t), t)
+}
+
+// definedName reports whether t has already been added to the definitions.
+func (n *Reflector) definedName(definitions Definitions, t reflect.Type) (string, bool) {
+	name := n.TypeName(t)
+	if n.namer != nil {
+		var ok bool
+		if name, ok = n.namer.lookup(t); !ok {
+			return "", false
+		}
+	}
+	_, ok := definitions[name]
+	return name, ok
+}
+
 func (n *Reflector) TypeName(t reflect.Type) string {
 	if n.TypeNamer != nil {
 		if name := n.TypeNamer(t); name != "" {
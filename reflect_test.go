@@ -313,3 +313,49 @@ func TestBaselineUnmarshal(t *testing.T) {
 	actualJSON, _ := json.MarshalIndent(actualSchema, "", "  ")
 	require.Equal(t, strings.ReplaceAll(string(expectedJSON), `\/`, "/"), string(actualJSON))
 }
+
+type UnsupportedItem struct {
+	Name  string     `json:"name"`
+	Price complex128 `json:"price"`
+}
+
+type UnsupportedOrder struct {
+	Items []UnsupportedItem           `json:"items"`
+	Hooks map[string]func()           `json:"hooks,omitempty"`
+	Done  chan struct{}               `json:"-"`
+	Extra map[string]*UnsupportedItem `json:"extra,omitempty"`
+}
+
+func TestReflectUnsupportedType(t *testing.T) {
+	_, err := ReflectE(&UnsupportedOrder{})
+	require.EqualError(t, err, "UnsupportedOrder.Items[].Price: unsupported type complex128")
+	var typeErr *UnsupportedTypeError
+	require.ErrorAs(t, err, &typeErr)
+	require.Equal(t, reflect.TypeOf(complex128(0)), typeErr.Type)
+
+	require.Panics(t, func() { Reflect(&UnsupportedOrder{}) })
+
+	s, err := (&Reflector{IgnoreUnsupportedFields: true}).ReflectE(&UnsupportedOrder{})
+	require.NoError(t, err)
+	item := s.Components["UnsupportedItem"]
+	require.NotNil(t, item)
+	_, ok := item.Properties.Get("price")
+	require.False(t, ok)
+	order := s.Components["UnsupportedOrder"]
+	_, ok = order.Properties.Get("hooks")
+	require.False(t, ok)
+
+	mapper := &Reflector{TypeMapper: func(t reflect.Type) *models.Schema {
+		if t.Kind() == reflect.Complex128 {
+			panic("boom")
+		}
+		return nil
+	}}
+	_, err = mapper.ReflectE(&UnsupportedOrder{})
+	require.EqualError(t, err, "UnsupportedOrder.Items[].Price: boom")
+	require.Panics(t, func() { mapper.Reflect(&UnsupportedOrder{}) })
+	doc := NewDocument("test", "1.0.0")
+	doc.Reflector = mapper
+	_, err = doc.RegisterE(&TestRouter{Method: "POST", Path: "/orders", ReqStruct: UnsupportedOrder{}})
+	require.EqualError(t, err, "UnsupportedOrder.Items[].Price: boom")
+}
@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Chise1/openapi/models"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ComponentConflict 不同的go类型生成了同名component时的处理方式
+type ComponentConflict int
+
+const (
+	ConflictRename ComponentConflict = iota //自动改名,例如billing.User改为BillingUser
+	ConflictError                           //注册时返回错误
+)
+
+// componentNamer 在一次注册中为go类型分配components中的名字.
+// 已经注册到文档中的名字由doc提供,本次注册新增的名字在提交前只记录在这里.
+type componentNamer struct {
+	doc      *Document
+	conflict ComponentConflict
+	types    map[string]reflect.Type
+	names    map[reflect.Type]string
+	err      error
+}
+
+func newComponentNamer(doc *Document) *componentNamer {
+	return &componentNamer{
+		doc:      doc,
+		conflict: doc.Conflict,
+		types:    map[string]reflect.Type{},
+		names:    map[reflect.Type]string{},
+	}
+}
+
+// lookup 返回t已经分配的名字
+func (n *componentNamer) lookup(t reflect.Type) (string, bool) {
+	if name, ok := n.names[t]; ok {
+		return name, true
+	}
+	name, ok := n.doc.componentNames[t]
+	return name, ok
+}
+
+// owner 返回占用name的go类型,不是由go类型生成的schema返回nil
+func (n *componentNamer) owner(name string) (reflect.Type, bool) {
+	if t, ok := n.types[name]; ok {
+		return t, true
+	}
+	if t, ok := n.doc.componentTypes[name]; ok {
+		return t, true
+	}
+	_, ok := n.doc.spec.Components.Schemas[name]
+	return nil, ok
+}
+
+// claim 为t分配名字,base已经被其他类型占用时按conflict处理
+func (n *componentNamer) claim(base string, t reflect.Type) string {
+	if name, ok := n.lookup(t); ok {
+		return name
+	}
+	name := base
+	if owner, ok := n.owner(name); ok && owner != t {
+		if n.conflict == ConflictError {
+			if n.err == nil {
+				a, b := typeString(owner), typeString(t)
+				if a == b {
+					// 同一个包中函数内声明的同名类型,用字段区分
+					a, b = a+" "+typeFields(owner), b+" "+typeFields(t)
+				}
+				n.err = fmt.Errorf("openapi: component %q is generated by both %s and %s", base, a, b)
+			}
+			return base
+		}
+		name = packagePrefix(t) + base
+		for i := 2; ; i++ {
+			if _, ok := n.owner(name); !ok {
+				break
+			}
+			name = packagePrefix(t) + base + strconv.Itoa(i)
+		}
+	}
+	n.types[name] = t
+	n.names[t] = name
+	return name
+}
+
+// typeString 返回带完整包路径的类型名,用于错误信息
+func typeString(t reflect.Type) string {
+	if t == nil {
+		return "an existing schema"
+	}
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// typeFields 返回类型的结构,例如(struct { ID int; Name string })
+func typeFields(t reflect.Type) string {
+	if t.Kind() != reflect.Struct {
+		return "(" + t.Kind().String() + ")"
+	}
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fields = append(fields, f.Name+" "+f.Type.String())
+	}
+	return "(struct { " + strings.Join(fields, "; ") + " })"
+}
+
+// addComponents 把一次注册生成的schema合并到文档的components中,同名且内容相同的schema保留原来的
+func (n *Document) addComponents(components Definitions, namer *componentNamer) {
+	for name, schema := range components {
+		if old, ok := n.spec.Components.Schemas[name]; !ok || !sameSchema(old, schema) {
+			n.spec.Components.Schemas[name] = schema
+		}
+		if t, ok := namer.types[name]; ok {
+			n.componentTypes[name] = t
+			n.componentNames[t] = name
+		}
+	}
+}
+
+func sameSchema(a, b *models.Schema) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(x, y)
+}
+
+// packagePrefix 把包名转换为名字前缀,例如billing转为Billing
+func packagePrefix(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return camelCase(pkg)
+}
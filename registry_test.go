@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type Tag struct {
+	Label string `json:"label"`
+}
+
+type TagBody struct {
+	Local Tag        `json:"local"`
+	Model models.Tag `json:"model"`
+}
+
+func TestComponentConflictRename(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&TestRouter{Method: "POST", Path: "/local", ReqStruct: Tag{}})
+	doc.Register(&TestRouter{Method: "POST", Path: "/model", ReqStruct: models.Tag{}})
+	doc.Register(&TestRouter{Method: "POST", Path: "/both", ReqStruct: TagBody{}})
+
+	spec := doc.Snapshot()
+	require.Contains(t, spec.Components.Schemas, "Tag")
+	require.Contains(t, spec.Components.Schemas, "ModelsTag")
+	require.Len(t, spec.Components.Schemas, 5) // Tag, ModelsTag, ExternalDocumentation, TagBody, ReqStruct
+	require.Equal(t, models.REF_PREFIX+"ModelsTag", spec.Paths["/model"].Post.RequestBody.Content["application/json"].Schema.Ref)
+
+	body := spec.Components.Schemas["TagBody"]
+	local, _ := body.Properties.Get("local")
+	model, _ := body.Properties.Get("model")
+	require.Equal(t, models.REF_PREFIX+"Tag", local.(*models.Schema).Ref)
+	require.Equal(t, models.REF_PREFIX+"ModelsTag", model.(*models.Schema).Ref)
+}
+
+func TestComponentConflictError(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Conflict = ConflictError
+	_, err := doc.RegisterE(&TestRouter{Method: "POST", Path: "/local", ReqStruct: Tag{}})
+	require.NoError(t, err)
+	_, err = doc.RegisterE(&TestRouter{Method: "POST", Path: "/local", ReqStruct: Tag{}})
+	require.NoError(t, err)
+
+	_, err = doc.RegisterE(&TestRouter{Method: "POST", Path: "/model", ReqStruct: models.Tag{}})
+	require.EqualError(t, err, `openapi: component "Tag" is generated by both github.com/Chise1/openapi.Tag and github.com/Chise1/openapi/models.Tag`)
+	require.NotContains(t, doc.Snapshot().Paths, "/model")
+}
+
+func TestComponentConflictParams(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Conflict = ConflictError
+	{
+		type Item struct {
+			Name string `json:"name"`
+		}
+		_, err := doc.RegisterE(&TestRouter{Method: "POST", Path: "/a", ReqStruct: Item{}})
+		require.NoError(t, err)
+	}
+	{
+		// 参数结构体不会生成component,同名也不冲突
+		type Item struct {
+			ID int `json:"id" in:"query"`
+		}
+		_, err := doc.RegisterE(&TestRouter{Method: "GET", Path: "/b", Param: Item{}})
+		require.NoError(t, err)
+		require.Contains(t, doc.Snapshot().Paths, "/b")
+		require.NotContains(t, doc.Snapshot().Components.Schemas, "OpenapiItem")
+
+		_, err = doc.RegisterE(&TestRouter{Method: "POST", Path: "/c", ReqStruct: Item{}})
+		require.EqualError(t, err, `openapi: component "Item" is generated by both github.com/Chise1/openapi.Item (struct { Name string }) and github.com/Chise1/openapi.Item (struct { ID int })`)
+	}
+}
@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// ResponseError 响应和文档中声明的不一致
+type ResponseError struct {
+	Method   string
+	Path     string //请求的路径
+	Status   int
+	Problems []string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("openapi: response %d for %s %s: %s", e.Status, e.Method, e.Path, strings.Join(e.Problems, "; "))
+}
+
+// ValidateResponse 按文档中注册的operation校验响应的状态码,content type,body和必须的header.
+// 请求不在文档中时也返回*ResponseError
+func (n *Document) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	spec := n.Snapshot()
+	e := &ResponseError{Method: r.Method, Path: r.URL.Path, Status: status}
+	oper, _, ok := n.findOperation(spec, r.Method, r.URL.EscapedPath())
+	if !ok {
+		e.Problems = append(e.Problems, "no operation is registered for the request")
+		return e
+	}
+	res, err := findResponse(spec.Components, oper.Responses, status)
+	if err != nil {
+		e.Problems = append(e.Problems, err.Error())
+		return e
+	}
+	if res == nil {
+		e.Problems = append(e.Problems, "status is not declared")
+		return e
+	}
+	e.Problems = n.validateResponse(spec, res, header, body)
+	if len(e.Problems) > 0 {
+		return e
+	}
+	return nil
+}
+
+// findResponse 按状态码查找声明的响应,依次尝试200,2XX和default,引用的共用响应会被解析
+func findResponse(components *models.Components, responses map[string]*models.Response, status int) (*models.Response, error) {
+	for _, key := range []string{statusKey(status), statusKey(status / 100), statusKey(StatusDefault)} {
+		if res, ok := responses[key]; ok {
+			return resolveResponse(components, res)
+		}
+	}
+	return nil, nil
+}
+
+func (n *Document) validateResponse(spec *models.OpenAPI, res *models.Response, header http.Header, body []byte) []string {
+	v := n.validator(spec)
+	var problems []string
+	for _, name := range sortedHeaders(res.Headers) {
+		h := res.Headers[name]
+		if h == nil || strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		values := header.Values(name)
+		if len(values) == 0 {
+			if h.Required {
+				problems = append(problems, "header "+name+" is required")
+			}
+			continue
+		}
+		value, err := coerceParam(spec.Components.Schemas, h.Schema, values)
+		if err != nil {
+			problems = append(problems, "header "+name+" "+err.Error())
+			continue
+		}
+		for _, e := range v.Validate(h.Schema, value) {
+			problems = append(problems, "header "+name+e.Pointer+" "+e.Message)
+		}
+	}
+	if len(res.Content) == 0 {
+		if len(body) > 0 {
+			problems = append(problems, "body is not declared")
+		}
+		return problems
+	}
+	contentType, _, media := matchContentType(res.Content, header.Get("Content-Type"))
+	if media == nil {
+		return append(problems, fmt.Sprintf("content type %q is not declared, want one of %v", contentType, sortedContent(res.Content)))
+	}
+	if !isJSON(contentType) || media.Schema == nil {
+		return problems
+	}
+	if len(body) == 0 {
+		return append(problems, "body is empty")
+	}
+	instance, err := decodeJSON(body)
+	if err != nil {
+		return append(problems, "body "+err.Error())
+	}
+	for _, e := range v.Validate(media.Schema, instance) {
+		problems = append(problems, "body"+e.Pointer+" "+e.Message)
+	}
+	return problems
+}
+
+func sortedHeaders(m map[string]*models.Header) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedContent(m map[string]*models.MediaType) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,173 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Handler 路由的处理函数,para和body为解码后的请求参数和body的指针,路由没有声明或者请求中没有body时为nil.
+// 返回响应参数和响应body,响应参数中带header tag的字段写入响应头,
+// body为error时返回RFC 7807格式的错误,*Problem使用自己的状态码,其他error返回500
+type Handler func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{})
+
+// Mux 可以注册http.Handler的路由器,例如*http.ServeMux
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// Router 把路由同时注册到文档和Mux上,请求按文档中的path模板分发,
+// 校验通过后把参数和body解码为路由声明的类型再调用Handler
+type Router struct {
+	doc *Document
+	mux Mux
+
+	mu       sync.RWMutex
+	routes   map[string]*boundRoute //"METHOD path" -> route
+	patterns map[string]bool        //已经注册到mux的pattern
+	codecs   codecs
+	config   validateConfig
+}
+
+// boundRoute 注册到Router上的一个路由
+type boundRoute struct {
+	method   string
+	template *pathTemplate
+	route    RouteStruct
+	handler  Handler
+	binder   *binder
+}
+
+// NewRouter 创建Router,mux为nil时只能把Router本身作为http.Handler使用.opts设置请求校验的选项,例如body的大小上限
+func NewRouter(doc *Document, mux Mux, opts ...ValidateOption) *Router {
+	return &Router{
+		doc:      doc,
+		mux:      mux,
+		routes:   map[string]*boundRoute{},
+		patterns: map[string]bool{},
+		config:   newValidateConfig(opts),
+	}
+}
+
+// Document 返回Router使用的文档
+func (n *Router) Document() *Document {
+	return n.doc
+}
+
+// Handle 把路由注册到文档,并把handler注册到mux,同一个method和path再次注册时替换原来的handler
+func (n *Router) Handle(route RouteStruct, handler Handler) error {
+	if handler == nil {
+		return errNilHandler
+	}
+	if _, err := n.doc.RegisterE(route); err != nil {
+		return err
+	}
+	method := strings.ToUpper(route.GetMethod())
+	if method == "" {
+		method = http.MethodGet
+	}
+	template, err := parsePath(route.GetPath())
+	if err != nil {
+		return err
+	}
+	b := &boundRoute{
+		method:   method,
+		template: template,
+		route:    route,
+		handler:  handler,
+		binder:   newBinder(route, &n.codecs),
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.routes[method+" "+template.path] = b
+	if n.mux != nil {
+		pattern := muxPattern(template.path)
+		if !n.patterns[pattern] {
+			n.patterns[pattern] = true
+			n.mux.Handle(pattern, n)
+		}
+	}
+	return nil
+}
+
+// muxPattern 返回path模板在mux上注册的pattern,
+// 带参数的模板注册第一个参数之前的前缀,例如/users/{id}注册为/users/
+func muxPattern(path string) string {
+	i := strings.IndexByte(path, '{')
+	if i < 0 {
+		return path
+	}
+	return path[:strings.LastIndexByte(path[:i], '/')+1]
+}
+
+// match 查找请求对应的路由,多个path模板都能匹配时优先使用静态部分最多的模板.
+// 没有找到时返回路径能匹配的method,用于405响应
+func (n *Router) match(method, path string) (*boundRoute, map[string]string, []string) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	var (
+		found   *boundRoute
+		vars    map[string]string
+		static  = -1
+		allowed []string
+	)
+	for _, b := range n.routes {
+		v, score, ok := matchPath(b.template.path, path, b.template.catchAll)
+		if !ok {
+			continue
+		}
+		if b.method != method {
+			allowed = append(allowed, b.method)
+			continue
+		}
+		if score < static || (score == static && b.template.path > found.template.path) {
+			continue
+		}
+		found, vars, static = b, v, score
+	}
+	sort.Strings(allowed)
+	// 多个模板都能匹配时同一个method只保留一个
+	methods := allowed[:0]
+	for i, method := range allowed {
+		if i == 0 || method != allowed[i-1] {
+			methods = append(methods, method)
+		}
+	}
+	return found, vars, methods
+}
+
+func (n *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, vars, allowed := n.match(r.Method, r.URL.EscapedPath())
+	if b == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			NewProblem(http.StatusMethodNotAllowed, "").ServeHTTP(w, r)
+			return
+		}
+		NewProblem(http.StatusNotFound, "").ServeHTTP(w, r)
+		return
+	}
+	n.config.limitBody(r)
+	spec := n.doc.Snapshot()
+	oper := spec.Paths[b.template.path].GetOperation(b.method)
+	if problem := n.doc.validateRequest(spec, oper, vars, r); problem != nil {
+		problem.Instance = r.URL.Path
+		problem.ServeHTTP(w, r)
+		return
+	}
+	form := r.MultipartForm
+	para, body, problem := b.binder.bind(n.doc.validator(spec), spec, oper, vars, r)
+	if r.MultipartForm != nil && r.MultipartForm != form {
+		// r可能不是http.Server创建的请求,不能依赖它删除临时文件
+		defer r.MultipartForm.RemoveAll()
+	}
+	if problem != nil {
+		problem.Instance = r.URL.Path
+		problem.ServeHTTP(w, r)
+		return
+	}
+	resPara, resBody := b.handler(r.Context(), para, body)
+	b.binder.write(w, r, spec, oper, resPara, resBody)
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type HelloHeaders struct {
+	RequestID string   `header:"X-Request-Id"`
+	Vary      []string `header:"Vary"`
+	Skipped   string
+}
+
+type FormRouter struct {
+	TestRouter
+}
+
+func (n *FormRouter) GetReqBody() interface{} {
+	return FormBody{}
+}
+
+type FormBody struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (FormBody) GetContentType() ContentType {
+	return Form
+}
+
+func TestRouterHandle(t *testing.T) {
+	mux := http.NewServeMux()
+	router := NewRouter(NewDocument("hello", "1.0.0"), mux)
+	err := router.Handle(&TestRouter{
+		Method:    "POST",
+		Path:      "/hello/{name}",
+		Param:     ReqParam{},
+		ReqStruct: ReqStruct{},
+	}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		p := para.(*ReqParam)
+		b := body.(*ReqStruct)
+		b.Hello = p.Name + ":" + b.Hello + ":" + p.Header
+		require.Equal(t, uint(1), p.Old)
+		require.Equal(t, uint(2), p.Old2)
+		require.Equal(t, 1.5, p.Cook)
+		return &HelloHeaders{RequestID: "abc", Vary: []string{"Accept", "Cookie"}, Skipped: "x"}, b
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/hello/{name}", Param: PathName{}}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		require.Equal(t, "bob", para.(*PathName).Name)
+		require.Nil(t, body)
+		return nil, errors.New("database is down")
+	}))
+	require.NoError(t, router.Handle(&FormRouter{TestRouter{Method: "PUT", Path: "/hello/{name}", Param: PathName{}}}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		b := body.(*FormBody)
+		return nil, ReqStruct{Hello: b.Name + strings.Repeat("!", b.Age)}
+	}))
+	_, _, ok := router.Document().FindOperation("POST", "/hello/a")
+	require.True(t, ok)
+
+	req := httptest.NewRequest("POST", "/hello/bob?old=1&old2=2", strings.NewReader(`{"hello":"hi"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("header", "h")
+	req.AddCookie(&http.Cookie{Name: "cook", Value: "1.5"})
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Equal(t, "abc", rec.Header().Get("X-Request-Id"))
+	require.Equal(t, []string{"Accept", "Cookie"}, rec.Header().Values("Vary"))
+	require.JSONEq(t, `{"hello":"bob:hi:h"}`, rec.Body.String())
+
+	req = httptest.NewRequest("POST", "/hello/bob?old=x", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	req = httptest.NewRequest("PUT", "/hello/bob", strings.NewReader(`name=bob&age=2`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"bob!!"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/hello/bob", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NotContains(t, rec.Body.String(), "database")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("DELETE", "/hello/bob", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, "GET, POST, PUT", rec.Header().Get("Allow"))
+
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/hello/me"}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		return nil, nil
+	}))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("DELETE", "/hello/me", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, "GET, POST, PUT", rec.Header().Get("Allow"))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/hello/bob/x", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRouterPointerParams(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	_, err := doc.RegisterE(&TestRouter{Method: "GET", Path: "/names/{name}", Param: &PathName{}})
+	require.NoError(t, err)
+	params := doc.Snapshot().Paths["/names/{name}"].Get.Parameters
+	require.Len(t, params, 1)
+	require.Equal(t, "name", params[0].Name)
+	require.Equal(t, "path", params[0].In)
+	_, err = doc.RegisterE(&TestRouter{Method: "GET", Path: "/nil/{name}", Param: (*PathName)(nil)})
+	require.NoError(t, err)
+
+	router := NewRouter(NewDocument("test", "1.0.0"), nil)
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/names/{name}", Param: &PathName{}}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		return nil, ReqStruct{Hello: para.(*PathName).Name}
+	}))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/names/bob", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"hello":"bob"}`, rec.Body.String())
+}
+
+func TestRouterMaxBodySize(t *testing.T) {
+	router := NewRouter(NewDocument("hello", "1.0.0"), nil, WithMaxBodySize(8))
+	require.NoError(t, router.Handle(&TestRouter{Method: "POST", Path: "/hello", ReqStruct: ReqStruct{}}, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		return nil, body
+	}))
+	req := httptest.NewRequest("POST", "/hello", strings.NewReader(`{"hello":"hi"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, rec.Body.String())
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+}
+
+func TestMuxPattern(t *testing.T) {
+	require.Equal(t, "/users", muxPattern("/users"))
+	require.Equal(t, "/users/", muxPattern("/users/{id}"))
+	require.Equal(t, "/files/", muxPattern("/files/{name}.{ext}"))
+	require.Equal(t, "/", muxPattern("/{id}"))
+	require.Equal(t, "/a/", muxPattern("/a/b{id}"))
+}
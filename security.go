@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"fmt"
+	"github.com/Chise1/openapi/models"
+	"sort"
+	"strings"
+)
+
+// WithSecurity 设置全局的安全需求,满足其中任意一个即可,operation可以覆盖
+func WithSecurity(requirements ...models.SecurityRequirement) Option {
+	return func(spec *models.OpenAPI) {
+		spec.Security = append(spec.Security, requirements...)
+	}
+}
+
+// AddSecurityScheme 在components.securitySchemes中添加安全方案,需要在引用它的路由注册之前添加
+func (n *Document) AddSecurityScheme(name string, scheme *models.SecurityScheme) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := checkSchemeVersion(n.spec.Openapi, scheme); err != nil {
+		return fmt.Errorf("openapi: security scheme %q: %v", name, err)
+	}
+	// 快照共享了components,先复制一份再修改
+	components := *n.spec.Components
+	components.SecuritySchemes = make(map[string]*models.SecurityScheme, len(n.spec.Components.SecuritySchemes)+1)
+	for k, v := range n.spec.Components.SecuritySchemes {
+		components.SecuritySchemes[k] = v
+	}
+	if err := components.SetSecuritySchemesE(name, scheme); err != nil {
+		return err
+	}
+	n.spec.Components = &components
+	n.snapshot = nil
+	return nil
+}
+
+// checkSchemeVersion mutualTLS是openapi 3.1新增的类型,3.0的文档中不能使用
+func checkSchemeVersion(version string, scheme *models.SecurityScheme) error {
+	if scheme != nil && scheme.Type == models.MutualTLS && !strings.HasPrefix(version, "3.1") {
+		return fmt.Errorf("type mutualTLS requires openapi 3.1, the document is %s", version)
+	}
+	return nil
+}
+
+// checkSecurity 检查安全需求引用的方案是否都已经注册,以及oauth2的scope是否在flows中定义
+func checkSecurity(components *models.Components, requirements []models.SecurityRequirement) error {
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			scheme, ok := components.SecuritySchemes[name]
+			if !ok {
+				return fmt.Errorf("security scheme %q is not declared in components.securitySchemes", name)
+			}
+			if scheme.Type != models.Oauth2 {
+				continue
+			}
+			for _, scope := range requirement[name] {
+				if !scheme.HasScope(scope) {
+					return fmt.Errorf("scope %q is not declared in the flows of security scheme %q", scope, name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+type SecureRouter struct {
+	TestRouter
+	Security []models.SecurityRequirement
+}
+
+func (n *SecureRouter) GetSecurity() []models.SecurityRequirement {
+	return n.Security
+}
+
+func TestDocumentSecurity(t *testing.T) {
+	doc := NewDocument("test", "1.0.0", WithSecurity(models.SecurityRequirement{"bearer": {}}))
+	require.Error(t, doc.Validate())
+
+	_, err := doc.RegisterE(&SecureRouter{
+		TestRouter: TestRouter{Method: "GET", Path: "/users"},
+		Security:   []models.SecurityRequirement{{"oauth": {"users:read"}}},
+	})
+	require.EqualError(t, err, `openapi: GET /users: security scheme "oauth" is not declared in components.securitySchemes`)
+
+	require.NoError(t, doc.AddSecurityScheme("bearer", models.NewHTTPBearer("JWT")))
+	require.NoError(t, doc.AddSecurityScheme("oauth", models.NewOAuth2(models.OAuthFlows{
+		ClientCredentials: &models.OAuthFlow{
+			TokenUrl: "https://example.com/token",
+			Scopes:   map[string]string{"users:read": "read users"},
+		},
+	})))
+	require.Error(t, doc.AddSecurityScheme("bad", &models.SecurityScheme{Type: models.Http}))
+	_, err = doc.RegisterE(&SecureRouter{
+		TestRouter: TestRouter{Method: "GET", Path: "/users"},
+		Security:   []models.SecurityRequirement{{"oauth": {"users:write"}}},
+	})
+	require.EqualError(t, err, `openapi: GET /users: scope "users:write" is not declared in the flows of security scheme "oauth"`)
+	doc.Register(&SecureRouter{
+		TestRouter: TestRouter{Method: "GET", Path: "/users"},
+		Security:   []models.SecurityRequirement{{"oauth": {"users:read"}}, {}},
+	})
+	require.NoError(t, doc.Validate())
+
+	spec := doc.Snapshot()
+	b, err := json.Marshal(spec.Paths["/users"].Get.Security)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"oauth": ["users:read"]}, {}]`, string(b))
+	b, err = json.Marshal(spec.Security)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"bearer": []}]`, string(b))
+}
+
+func TestSecuritySchemeJSON(t *testing.T) {
+	tests := []struct {
+		scheme *models.SecurityScheme
+		json   string
+	}{
+		{models.NewHTTPBearer("JWT"), `{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}`},
+		{models.NewHTTPBasic(), `{"type": "http", "scheme": "basic"}`},
+		{models.NewAPIKey("X-API-Key", models.APIKeyInHeader), `{"type": "apiKey", "name": "X-API-Key", "in": "header"}`},
+		{models.NewOpenIdConnect("https://example.com/.well-known/openid-configuration"), `{"type": "openIdConnect", "openIdConnectUrl": "https://example.com/.well-known/openid-configuration"}`},
+		{models.NewMutualTLS(), `{"type": "mutualTLS"}`},
+		{&models.SecurityScheme{Type: models.Http, Scheme: "Bearer", BearerFormat: "JWT"}, `{"type": "http", "scheme": "Bearer", "bearerFormat": "JWT"}`},
+		{models.NewOAuth2(models.OAuthFlows{
+			AuthorizationCode: &models.OAuthFlow{AuthorizationUrl: "https://example.com/auth", TokenUrl: "https://example.com/token"},
+		}), `{"type": "oauth2", "flows": {"authorizationCode": {"authorizationUrl": "https://example.com/auth", "tokenUrl": "https://example.com/token", "scopes": {}}}}`},
+	}
+	for _, tt := range tests {
+		require.NoError(t, tt.scheme.Validate())
+		b, err := json.Marshal(tt.scheme)
+		require.NoError(t, err)
+		require.JSONEq(t, tt.json, string(b))
+	}
+
+	invalid := []*models.SecurityScheme{
+		{Type: models.ApiKey, Name: "key"},
+		{Type: models.Http, Scheme: "basic", BearerFormat: "JWT"},
+		models.NewOAuth2(models.OAuthFlows{}),
+		models.NewOAuth2(models.OAuthFlows{Implicit: &models.OAuthFlow{TokenUrl: "https://example.com/token"}}),
+		{Type: models.Openidconnect},
+		{Type: "basic"},
+	}
+	for _, scheme := range invalid {
+		require.Error(t, scheme.Validate(), "%+v", scheme)
+	}
+}
+
+func TestMutualTLSRequires31(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	require.EqualError(t, doc.AddSecurityScheme("mtls", models.NewMutualTLS()),
+		`openapi: security scheme "mtls": type mutualTLS requires openapi 3.1, the document is 3.0.2`)
+	require.NotContains(t, doc.Snapshot().Components.SecuritySchemes, "mtls")
+}
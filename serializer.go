@@ -4,7 +4,6 @@ import (
 	"github.com/Chise1/openapi/models"
 	"github.com/iancoleman/orderedmap"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -19,26 +18,72 @@ type RouterHelper struct {
 	ReqContentType string
 }
 
+// NewOpenapiRequest 根据路由生成openapi需要的body,参数和返回值,遇到不支持的类型时panic
 func NewOpenapiRequest(v RouteStruct) *RouterHelper {
-	r := &Reflector{}
+	n, err := NewOpenapiRequestE(v)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// NewOpenapiRequestE 和NewOpenapiRequest一样,遇到不支持的类型时返回*UnsupportedTypeError
+func NewOpenapiRequestE(v RouteStruct) (*RouterHelper, error) {
+	return newRouterHelper(&Reflector{}, v)
+}
+
+func newRouterHelper(r *Reflector, v RouteStruct) (*RouterHelper, error) {
 	n := &RouterHelper{}
 	reqBody := v.GetReqBody()
 	if reqBody != nil {
-		body, ok := reqBody.(IContentType)
-		if ok {
-			n.reqBody(r, body, body.GetContentType())
-		} else {
-			n.reqBody(r, reqBody, Json)
+		err := catchUnsupported(reflect.TypeOf(reqBody), func() {
+			n.reqBody(r, reqBody)
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 	reqpara := v.GetReqPara()
 	if reqpara != nil {
-		n.para(r, v.GetReqPara())
+		var paraErr error
+		err := catchUnsupported(reflect.TypeOf(reqpara), func() {
+			paraErr = n.para(r, reqpara)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if paraErr != nil {
+			return nil, paraErr
+		}
 	}
 
 	n.Response = map[string]*models.Response{}
-	n.WriteRes(v.GetResBody())
-	return n
+	if err := n.writeRes(r, v.GetResBody()); err != nil {
+		return nil, err
+	}
+	if l, ok := v.(RouteLinks); ok {
+		for status, links := range l.GetLinks() {
+			res := n.response(status)
+			if res.Links == nil {
+				res.Links = map[string]*models.Link{}
+			}
+			for name, link := range links {
+				res.Links[name] = link
+			}
+		}
+	}
+	var descriptions map[int]string
+	if d, ok := v.(RouteResponseDescriptions); ok {
+		descriptions = d.GetResDescriptions()
+	}
+	for status, description := range descriptions {
+		n.response(status).Description = description
+	}
+	// 所有响应都创建后再加响应头,只在links或者描述中声明的状态码也有共用的响应头
+	if err := n.resHeaders(r, v); err != nil {
+		return nil, err
+	}
+	return n, nil
 }
 func (n *RouterHelper) GetSchema() *models.Schema {
 	if n.Schema.Ref != "" {
@@ -46,39 +91,65 @@ func (n *RouterHelper) GetSchema() *models.Schema {
 	}
 	return n.Schema
 }
+
+// WriteRes 生成各个状态码的返回值,遇到不支持的类型时panic
 func (n *RouterHelper) WriteRes(exceptRes map[int]interface{}) {
+	if err := n.writeRes(&Reflector{}, exceptRes); err != nil {
+		panic(err)
+	}
+}
+
+func (n *RouterHelper) writeRes(reflector *Reflector, exceptRes map[int]interface{}) error {
 	if exceptRes == nil {
-		return
+		return nil
 	}
-	for status, res := range exceptRes {
+	// 按状态码和媒体类型的顺序生成,重名的component每次得到相同的名字
+	for _, status := range sortedStatuses(exceptRes) {
+		res := exceptRes[status]
 		if res == nil {
+			n.response(status)
 			continue
 		}
-		exceptSchema := Reflect(res)
-		n.updateComponents(exceptSchema.Components)
-		body, ok := res.(IContentType)
-		t := ""
-		if ok {
-			t = string(body.GetContentType())
-		} else {
-			t = "application/json"
+		content := map[string]*models.MediaType{}
+		body := bodyContent(res)
+		for _, t := range body.sortedTypes() {
+			value := body[t]
+			exceptSchema, err := reflector.ReflectE(value)
+			if err != nil {
+				return err
+			}
+			n.updateComponents(exceptSchema.Components)
+			content[string(t)] = &models.MediaType{
+				Schema:  exceptSchema.Schema,
+				Example: value,
+			}
 		}
-		n.Response[strconv.Itoa(status)] = &models.Response{
-			Content: map[string]*models.MediaType{
-				t: {
-					Schema:  exceptSchema.Schema,
-					Example: res,
-				},
-			},
+		n.Response[statusKey(status)] = &models.Response{
+			Description: statusDescription(status),
+			Content:     content,
 		}
 	}
+	return nil
 }
-func (n *RouterHelper) reqBody(reflector *Reflector, v interface{}, reqType ContentType) {
-	body := n.body(reflector, v, reqType)
-	for k, v := range body {
-		n.ReqContentType = k
-		n.Schema = v.Schema
+
+// reqBody 生成请求的body,v为Content时每个媒体类型分别生成schema
+func (n *RouterHelper) reqBody(reflector *Reflector, v interface{}) {
+	content := bodyContent(v)
+	if len(content) == 0 {
+		return
 	}
+	body := map[string]*models.MediaType{}
+	for _, t := range content.sortedTypes() {
+		if t == Form || t == FormData {
+			body[string(t)] = n.formBody(reflector, content[t], t)
+			continue
+		}
+		body[string(t)] = n.body(reflector, content[t])
+	}
+	// ReqContentType和Schema使用排序后的第一个媒体类型
+	first := content.sortedTypes()[0]
+	n.ReqContentType = string(first)
+	n.Schema = body[string(first)].Schema
 	n.Body = &models.RequestBody{
 		Content: body,
 	}
@@ -92,20 +163,21 @@ func (n *RouterHelper) updateComponents(components map[string]*models.Schema) {
 		n.Components[name] = schema
 	}
 }
-func (n *RouterHelper) body(reflector *Reflector, v interface{}, reqType ContentType) map[string]*models.MediaType {
+func (n *RouterHelper) body(reflector *Reflector, v interface{}) *models.MediaType {
 	var components Definitions = map[string]*models.Schema{}
 	vType := reflect.TypeOf(v)
 	schema := reflector.reflectTypeToSchema(components, vType)
 	n.updateComponents(components)
 	rootSchema := n.GetSchemaStruct(schema)
-	return map[string]*models.MediaType{
-		string(reqType): {
-			Schema:  schema,
-			Example: GetExample(rootSchema, v),
-		},
+	if rootSchema == nil || rootSchema.Properties == nil {
+		return &models.MediaType{Schema: schema, Example: v}
+	}
+	return &models.MediaType{
+		Schema:  schema,
+		Example: GetExample(rootSchema, v),
 	}
 }
-func (n *RouterHelper) para(reflector *Reflector, v interface{}) {
+func (n *RouterHelper) para(reflector *Reflector, v interface{}) error {
 	components := Definitions{}
 	st := &models.Schema{
 		Version:              Version,
@@ -113,10 +185,11 @@ func (n *RouterHelper) para(reflector *Reflector, v interface{}) {
 		Properties:           orderedmap.New(),
 		AdditionalProperties: []byte("false"),
 	}
-	t := reflect.TypeOf(v)
+	value := indirect(v)
+	t := value.Type()
+	// 参数结构体本身不是component,只反射字段,不占用components中的名字
 	reflector.reflectStructFields(st, components, t)
-	reflector.reflectStruct(components, t)
-	delete(components, reflector.TypeName(t))
+	n.updateComponents(components) //对象类型的参数引用的schema
 	for _, name := range st.Properties.Keys() {
 		iproperty, _ := st.Properties.Get(name)
 		property := iproperty.(*models.Schema)
@@ -141,15 +214,20 @@ func (n *RouterHelper) para(reflector *Reflector, v interface{}) {
 				break
 			}
 		}
-		n.Parameters = append(n.Parameters, &models.Parameter{
+		param := &models.Parameter{
 			Schema:      property,
 			Name:        name,
 			In:          in,
 			Description: property.Description,
 			Required:    required,
-			Example:     v,
-		})
+			Example:     value.Interface(),
+		}
+		if err := paramSerialization(param, field, components); err != nil {
+			return err
+		}
+		n.Parameters = append(n.Parameters, param)
 	}
+	return nil
 }
 
 func (n *RouterHelper) GetSchemaStruct(schema *models.Schema) *models.Schema {
@@ -160,9 +238,23 @@ func (n *RouterHelper) GetSchemaStruct(schema *models.Schema) *models.Schema {
 	}
 	return s
 }
+
+// indirect 返回v解引用后的值,nil指针返回指向类型的零值
+func indirect(v interface{}) reflect.Value {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value = reflect.Zero(value.Type().Elem())
+			continue
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
 func GetExample(schema *models.Schema, v interface{}) map[string]interface{} {
 	res := map[string]interface{}{}
-	value := reflect.ValueOf(v)
+	value := indirect(v)
 	for _, key := range schema.Properties.Keys() {
 		prop, _ := schema.Properties.Get(key)
 		fieldSchema := prop.(*models.Schema)
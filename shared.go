@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// AddResponse 把共用的响应保存到components.responses中,所有operation的status都通过$ref引用它,
+// 包括已经注册的operation.路由自己声明了同一个status时使用路由的声明.
+// 同一个status再次添加时替换原来的引用,name已经被其他status或者components.responses使用时返回错误.
+// status可以使用StatusDefault和Status4XX这样的特殊状态码,description为空时使用默认描述
+func (n *Document) AddResponse(status int, name, description string, body interface{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	key := statusKey(status)
+	old := n.shared[key]
+	if _, ok := n.spec.Components.Responses[name]; ok && (old == nil || old.Ref != responseRefPrefix+name) {
+		return fmt.Errorf("openapi: response %q is already used by another status or component", name)
+	}
+	namer := newComponentNamer(n)
+	reflector := *n.reflector()
+	reflector.namer = namer
+	helper := &RouterHelper{Response: map[string]*models.Response{}}
+	if err := helper.writeRes(&reflector, map[int]interface{}{status: body}); err != nil {
+		return err
+	}
+	if namer.err != nil {
+		return namer.err
+	}
+	res := helper.Response[key]
+	if res == nil {
+		res = &models.Response{}
+	}
+	res.Description = description
+	if res.Description == "" {
+		res.Description = statusDescription(status)
+	}
+	n.addComponents(helper.Components, namer)
+	if n.spec.Components.Responses == nil {
+		n.spec.Components.Responses = map[string]*models.Response{}
+	}
+	n.spec.Components.Responses[name] = res
+	n.shared[key] = &models.Response{Ref: responseRefPrefix + name}
+	// 快照里共享了PathItem和Operation,复制一份再修改
+	for path, item := range n.spec.Paths {
+		copied := *item
+		for _, method := range models.Methods {
+			if oper := copied.GetOperation(method); oper != nil {
+				o := *oper
+				if old != nil && o.Responses[key] == old {
+					// 替换之前加入的引用,路由自己的声明不变
+					responses := make(map[string]*models.Response, len(o.Responses))
+					for k, v := range o.Responses {
+						responses[k] = v
+					}
+					delete(responses, key)
+					o.Responses = responses
+				}
+				n.addSharedResponses(&o)
+				copied.SetOperation(method, &o)
+			}
+		}
+		n.spec.Paths[path] = &copied
+	}
+	n.snapshot = nil
+	return nil
+}
+
+// addSharedResponses 把共用的响应加到operation中,会替换oper.Responses.
+// 所有operation共享n.shared中的同一个$ref对象,用来区分路由自己声明的响应
+func (n *Document) addSharedResponses(oper *models.Operation) {
+	if len(n.shared) == 0 {
+		return
+	}
+	responses := make(map[string]*models.Response, len(oper.Responses)+len(n.shared))
+	for key, res := range oper.Responses {
+		responses[key] = res
+	}
+	for key, ref := range n.shared {
+		if _, ok := responses[key]; !ok {
+			responses[key] = ref
+		}
+	}
+	oper.Responses = responses
+}
+
+const responseRefPrefix = "#/components/responses/"
+
+// resolveResponse 解析引用components.responses的响应
+func resolveResponse(components *models.Components, res *models.Response) (*models.Response, error) {
+	for i := 0; res != nil && res.Ref != "" && i < maxRefDepth; i++ {
+		name := strings.TrimPrefix(res.Ref, responseRefPrefix)
+		if components == nil || components.Responses[name] == nil || name == res.Ref {
+			return nil, fmt.Errorf("unresolvable reference %q", res.Ref)
+		}
+		res = components.Responses[name]
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// GetResBody等按状态码声明响应的地方可以使用的特殊状态码
+const (
+	StatusDefault = 0 //default,没有单独声明的状态码都使用这个响应
+	Status1XX     = 1 //1XX
+	Status2XX     = 2 //2XX
+	Status3XX     = 3 //3XX
+	Status4XX     = 4 //4XX
+	Status5XX     = 5 //5XX
+)
+
+// rangeDescriptions 范围状态码的默认描述
+var rangeDescriptions = map[int]string{
+	Status1XX: "Informational",
+	Status2XX: "Success",
+	Status3XX: "Redirection",
+	Status4XX: "Client Error",
+	Status5XX: "Server Error",
+}
+
+// statusKey 返回状态码在responses中的key,例如200,4XX和default
+func statusKey(status int) string {
+	switch {
+	case status == StatusDefault:
+		return "default"
+	case status >= Status1XX && status <= Status5XX:
+		return strconv.Itoa(status) + "XX"
+	}
+	return strconv.Itoa(status)
+}
+
+// isStatusCode 判断是否为具体的状态码,而不是default或者范围
+func isStatusCode(status int) bool {
+	return status >= 100
+}
+
+// statusDescription 返回状态码的默认描述,具体的状态码使用http.StatusText
+func statusDescription(status int) string {
+	if status == StatusDefault {
+		return "Default Response"
+	}
+	if d, ok := rangeDescriptions[status]; ok {
+		return d
+	}
+	if d := http.StatusText(status); d != "" {
+		return d
+	}
+	return "Status " + strconv.Itoa(status)
+}
+
+// sortedStatuses 返回排序后的状态码
+func sortedStatuses(m map[int]interface{}) []int {
+	statuses := make([]int, 0, len(m))
+	for status := range m {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	return statuses
+}
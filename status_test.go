@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type StatusRouter struct {
+	TestRouter
+	Bodies       map[int]interface{}
+	Descriptions map[int]string
+}
+
+func (n *StatusRouter) GetResBody() map[int]interface{}    { return n.Bodies }
+func (n *StatusRouter) GetResDescriptions() map[int]string { return n.Descriptions }
+
+func TestStatusKey(t *testing.T) {
+	require.Equal(t, "default", statusKey(StatusDefault))
+	require.Equal(t, "4XX", statusKey(Status4XX))
+	require.Equal(t, "201", statusKey(http.StatusCreated))
+	require.Equal(t, "Created", statusDescription(http.StatusCreated))
+	require.Equal(t, "Server Error", statusDescription(Status5XX))
+	require.Equal(t, "Status 599", statusDescription(599))
+}
+
+func TestResponseDescriptions(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&StatusRouter{
+		TestRouter: TestRouter{Method: "POST", Path: "/users"},
+		Bodies: map[int]interface{}{
+			http.StatusCreated:   ReqStruct{},
+			http.StatusNoContent: nil,
+			Status4XX:            ErrorBody{},
+			StatusDefault:        ErrorBody{},
+		},
+		Descriptions: map[int]string{http.StatusCreated: "用户已创建"},
+	})
+	doc.Register(&StatusRouter{TestRouter: TestRouter{Method: "GET", Path: "/users"}})
+	paths := doc.Snapshot().Paths
+
+	responses := paths["/users"].Post.Responses
+	require.Equal(t, "用户已创建", responses["201"].Description)
+	require.Equal(t, "No Content", responses["204"].Description)
+	require.Empty(t, responses["204"].Content)
+	require.Equal(t, "Client Error", responses["4XX"].Description)
+	require.Equal(t, "Default Response", responses["default"].Description)
+	require.Contains(t, responses["4XX"].Content, "application/json")
+
+	// 没有声明响应时使用DefaultRes
+	require.Equal(t, DefaultRes.Description, paths["/users"].Get.Responses["200"].Description)
+	require.NotSame(t, &DefaultRes, paths["/users"].Get.Responses["200"])
+}
+
+func TestSharedResponses(t *testing.T) {
+	doc := NewDocument("test", "1.0.0")
+	doc.Register(&TestRouter{Method: "GET", Path: "/users"})
+	require.NoError(t, doc.AddResponse(Status5XX, "ServerError", "", ErrorBody{}))
+	require.NoError(t, doc.AddResponse(http.StatusNotFound, "NotFound", "资源不存在", ErrorBody{}))
+	doc.Register(&StatusRouter{
+		TestRouter: TestRouter{Method: "POST", Path: "/users"},
+		Bodies:     map[int]interface{}{http.StatusOK: ReqStruct{}, http.StatusNotFound: ReqStruct{}},
+	})
+	spec := doc.Snapshot()
+	require.Equal(t, "Server Error", spec.Components.Responses["ServerError"].Description)
+	require.Equal(t, "资源不存在", spec.Components.Responses["NotFound"].Description)
+	require.Contains(t, spec.Components.Schemas, "ErrorBody")
+
+	get := spec.Paths["/users"].Get.Responses
+	require.Equal(t, "#/components/responses/ServerError", get["5XX"].Ref)
+	require.Equal(t, "#/components/responses/NotFound", get["404"].Ref)
+	post := spec.Paths["/users"].Post.Responses
+	require.Equal(t, "#/components/responses/ServerError", post["5XX"].Ref)
+	require.Empty(t, post["404"].Ref, "路由自己声明的状态码优先")
+	require.NoError(t, doc.Validate())
+
+	b, err := json.Marshal(ErrorBody{Code: 500, Message: "boom"})
+	require.NoError(t, err)
+	header := http.Header{"Content-Type": {"application/json"}}
+	req := httptest.NewRequest("GET", "/users", nil)
+	require.NoError(t, doc.ValidateResponse(req, http.StatusBadGateway, header, b))
+	require.Error(t, doc.ValidateResponse(req, http.StatusBadGateway, header, []byte(`{"code":"x"}`)))
+
+	// 同一个status换一个名字时,已经注册的operation也引用新的响应
+	require.NoError(t, doc.AddResponse(http.StatusNotFound, "Missing", "", ErrorBody{}))
+	require.NoError(t, doc.AddResponse(http.StatusNotFound, "Missing", "找不到", ErrorBody{}))
+	spec = doc.Snapshot()
+	require.Equal(t, "#/components/responses/Missing", spec.Paths["/users"].Get.Responses["404"].Ref)
+	require.Empty(t, spec.Paths["/users"].Post.Responses["404"].Ref, "路由自己声明的状态码优先")
+	require.Equal(t, "找不到", spec.Components.Responses["Missing"].Description)
+	require.EqualError(t, doc.AddResponse(http.StatusConflict, "Missing", "", ErrorBody{}),
+		`openapi: response "Missing" is already used by another status or component`)
+	require.EqualError(t, doc.AddResponse(http.StatusConflict, "NotFound", "", ErrorBody{}),
+		`openapi: response "NotFound" is already used by another status or component`)
+	require.NotContains(t, doc.Snapshot().Paths["/users"].Get.Responses, "409")
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// paramStyles 各个位置允许的style
+var paramStyles = map[string][]string{
+	"path":   {"simple", "label", "matrix"},
+	"query":  {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+	"header": {"simple"},
+	"cookie": {"form"},
+}
+
+// paramSerialization 从style,explode和allowReserved tag设置参数的序列化方式,并检查是否符合规范
+func paramSerialization(param *models.Parameter, field reflect.StructField, components Definitions) error {
+	param.Style = field.Tag.Get("style")
+	if v, ok := field.Tag.Lookup("explode"); ok {
+		explode, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("openapi: parameter %q: invalid explode tag %q", param.Name, v)
+		}
+		param.Explode = &explode
+	}
+	if v, ok := field.Tag.Lookup("allowReserved"); ok {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("openapi: parameter %q: invalid allowReserved tag %q", param.Name, v)
+		}
+		if allow && param.In != "query" {
+			return fmt.Errorf("openapi: parameter %q: allowReserved only applies to query parameters", param.Name)
+		}
+		param.AllowReserved = allow
+	}
+	if param.Style == "" {
+		return nil
+	}
+	allowed := paramStyles[param.In]
+	found := false
+	for _, style := range allowed {
+		found = found || style == param.Style
+	}
+	if !found {
+		return fmt.Errorf("openapi: parameter %q: style %q is not allowed in %s, want one of %s", param.Name, param.Style, param.In, strings.Join(allowed, ", "))
+	}
+	typ := schemaType(resolveSchema(components, param.Schema))
+	switch param.Style {
+	case "deepObject":
+		if typ != "object" {
+			return fmt.Errorf("openapi: parameter %q: style deepObject requires an object, got %s", param.Name, typeName(typ))
+		}
+		if param.Explode != nil && !*param.Explode {
+			return fmt.Errorf("openapi: parameter %q: style deepObject requires explode", param.Name)
+		}
+	case "spaceDelimited", "pipeDelimited":
+		if typ != "array" && typ != "object" {
+			return fmt.Errorf("openapi: parameter %q: style %s requires an array or object, got %s", param.Name, param.Style, typeName(typ))
+		}
+	}
+	return nil
+}
+
+func typeName(typ string) string {
+	if typ == "" {
+		return "a schema without type"
+	}
+	return typ
+}
@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Color struct {
+	R int `json:"R"`
+	G int `json:"G"`
+}
+
+type StylePara struct {
+	Label  []int    `json:"label" in:"path" style:"label" explode:"true"`
+	Matrix []string `json:"matrix" in:"path" style:"matrix"`
+	Color  Color    `json:"color" in:"path" style:"matrix" explode:"true"`
+	Ids    []int    `json:"ids,omitempty" in:"query"`
+	Tags   []string `json:"tags,omitempty" in:"query" explode:"false"`
+	Pipe   []string `json:"pipe,omitempty" in:"query" style:"pipeDelimited" explode:"false"`
+	Space  []int    `json:"space,omitempty" in:"query" style:"spaceDelimited" explode:"false"`
+	Filter Color    `json:"filter,omitempty" in:"query" style:"deepObject"`
+	Point  Color    `json:"point,omitempty" in:"header" explode:"true"`
+	Raw    string   `json:"raw,omitempty" in:"query" allowReserved:"true"`
+}
+
+func TestParamStyles(t *testing.T) {
+	router := NewRouter(NewDocument("styles", "1.0.0"), nil)
+	var got *StylePara
+	require.NoError(t, router.Handle(&TestRouter{Method: "GET", Path: "/{label}/{matrix}/{color}", Param: StylePara{}},
+		func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+			got = para.(*StylePara)
+			return nil, nil
+		}))
+	params := router.Document().Snapshot().Paths["/{label}/{matrix}/{color}"].Get.Parameters
+	b, _ := json.Marshal(params[0])
+	require.Contains(t, string(b), `"style":"label","explode":true`)
+	b, _ = json.Marshal(params[4])
+	require.Contains(t, string(b), `"explode":false`)
+	require.True(t, params[9].AllowReserved)
+
+	req := httptest.NewRequest("GET", "/.1.2/;matrix=a,b/;R=1;G=2?ids=1&ids=2&tags=x,y&pipe=a|b&space=1%202&filter[R]=3&filter[G]=4&raw=a/b", nil)
+	req.Header.Set("point", "R=5,G=6")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	require.Equal(t, &StylePara{
+		Label:  []int{1, 2},
+		Matrix: []string{"a", "b"},
+		Color:  Color{R: 1, G: 2},
+		Ids:    []int{1, 2},
+		Tags:   []string{"x", "y"},
+		Pipe:   []string{"a", "b"},
+		Space:  []int{1, 2},
+		Filter: Color{R: 3, G: 4},
+		Point:  Color{R: 5, G: 6},
+		Raw:    "a/b",
+	}, got)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/1,2/;matrix=a/;R=1;G=x", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, []InvalidParam{
+		{Name: "label", In: "path", Reason: `must start with "."`},
+		{Name: "color", In: "path", Reason: `G must be integer, got "x"`},
+	}, problem.InvalidParams)
+}
+
+type BadStyle struct {
+	Name string `json:"name" in:"header" style:"form"`
+}
+
+type BadDeepObject struct {
+	Name string `json:"name" in:"query" style:"deepObject"`
+}
+
+type BadExplode struct {
+	Name string `json:"name" in:"query" explode:"yes"`
+}
+
+type BadReserved struct {
+	Name string `json:"name" in:"header" allowReserved:"true"`
+}
+
+func TestParamStyleErrors(t *testing.T) {
+	doc := NewDocument("styles", "1.0.0")
+	for v, msg := range map[interface{}]string{
+		BadStyle{}:      `openapi: parameter "name": style "form" is not allowed in header, want one of simple`,
+		BadDeepObject{}: `openapi: parameter "name": style deepObject requires an object, got string`,
+		BadExplode{}:    `openapi: parameter "name": invalid explode tag "yes"`,
+		BadReserved{}:   `openapi: parameter "name": allowReserved only applies to query parameters`,
+	} {
+		_, err := doc.RegisterE(&TestRouter{Method: "GET", Path: "/", Param: v})
+		require.EqualError(t, err, msg)
+	}
+}
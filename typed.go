@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// TypedHandler Handle使用的处理函数,返回error时按Handler的规则返回RFC 7807格式的错误
+type TypedHandler[P, B, R any] func(ctx context.Context, params P, body B) (R, error)
+
+// RouteOption 设置Handle生成的路由的元数据
+type RouteOption func(route *typedRoute)
+
+// WithRouteDescription 设置operation的description
+func WithRouteDescription(description string) RouteOption {
+	return func(route *typedRoute) {
+		route.description = description
+	}
+}
+
+// WithRouteSummary 设置operation的summary
+func WithRouteSummary(summary string) RouteOption {
+	return func(route *typedRoute) {
+		route.summary = summary
+	}
+}
+
+// WithRouteTags 设置operation的tags
+func WithRouteTags(tags ...string) RouteOption {
+	return func(route *typedRoute) {
+		route.tags = append(route.tags, tags...)
+	}
+}
+
+// WithRouteOperationID 设置固定的operationId
+func WithRouteOperationID(id string) RouteOption {
+	return func(route *typedRoute) {
+		route.operationID = id
+	}
+}
+
+// WithRouteDeprecated 把operation标记为deprecated
+func WithRouteDeprecated() RouteOption {
+	return func(route *typedRoute) {
+		route.deprecated = true
+	}
+}
+
+// WithRouteSecurity 设置operation的安全需求
+func WithRouteSecurity(requirements ...models.SecurityRequirement) RouteOption {
+	return func(route *typedRoute) {
+		route.security = append(route.security, requirements...)
+	}
+}
+
+// WithRouteResponse 声明其他状态码的响应,例如错误返回的结构体
+func WithRouteResponse(status int, body interface{}) RouteOption {
+	return func(route *typedRoute) {
+		route.responses[status] = body
+	}
+}
+
+// WithRouteResponseDescription 覆盖状态码的默认描述
+func WithRouteResponseDescription(status int, description string) RouteOption {
+	return func(route *typedRoute) {
+		if route.descriptions == nil {
+			route.descriptions = map[int]string{}
+		}
+		route.descriptions[status] = description
+	}
+}
+
+// typedRoute Handle生成的路由,schema由类型参数的零值生成
+type typedRoute struct {
+	method       string
+	path         string
+	description  string
+	summary      string
+	operationID  string
+	tags         []string
+	deprecated   bool
+	security     []models.SecurityRequirement
+	para         interface{}
+	body         interface{}
+	responses    map[int]interface{}
+	descriptions map[int]string
+}
+
+func (n *typedRoute) GetReqPara() interface{}                   { return n.para }
+func (n *typedRoute) GetReqBody() interface{}                   { return n.body }
+func (n *typedRoute) GetResBody() map[int]interface{}           { return n.responses }
+func (n *typedRoute) GetResPara() interface{}                   { return nil }
+func (n *typedRoute) GetDescription() string                    { return n.description }
+func (n *typedRoute) GetPath() string                           { return n.path }
+func (n *typedRoute) GetMethod() string                         { return n.method }
+func (n *typedRoute) GetSummary() string                        { return n.summary }
+func (n *typedRoute) GetOperationID() string                    { return n.operationID }
+func (n *typedRoute) GetTags() []string                         { return n.tags }
+func (n *typedRoute) GetDeprecated() bool                       { return n.deprecated }
+func (n *typedRoute) GetSecurity() []models.SecurityRequirement { return n.security }
+func (n *typedRoute) GetResDescriptions() map[int]string        { return n.descriptions }
+
+// Handle 用类型参数声明路由并注册到router,P为参数,B为body,R为200的响应,
+// 使用struct{}表示没有参数,body或者响应内容.
+// P或者B为指针时,请求中没有对应的值也会传入指向零值的指针,不会是nil.
+// R为指针时handler返回nil和nil error会返回500.
+//
+//	openapi.Handle(router, "POST", "/users/{id}", func(ctx context.Context, p UserPara, b User) (User, error) {
+//		return b, nil
+//	})
+func Handle[P, B, R any](router *Router, method, path string, fn TypedHandler[P, B, R], opts ...RouteOption) error {
+	route := &typedRoute{
+		method:    method,
+		path:      path,
+		para:      zeroValue[P](),
+		body:      zeroValue[B](),
+		responses: map[int]interface{}{},
+	}
+	if res := zeroValue[R](); res != nil {
+		route.responses[200] = res
+	}
+	for _, opt := range opts {
+		opt(route)
+	}
+	return router.Handle(route, func(ctx context.Context, para interface{}, body interface{}) (interface{}, interface{}) {
+		res, err := fn(ctx, typedValue[P](para), typedValue[B](body))
+		if err != nil {
+			return nil, err
+		}
+		if zeroValue[R]() == nil {
+			return nil, nil
+		}
+		if v := reflect.ValueOf(res); v.Kind() == reflect.Ptr && v.IsNil() {
+			// 200的响应已经声明了内容,nil不是合法的响应
+			return nil, errNilResponse
+		}
+		return nil, res
+	})
+}
+
+var emptyStructType = reflect.TypeOf(struct{}{})
+
+var errNilResponse = errors.New("openapi: typed handler returned a nil response")
+
+// zeroValue 返回T的零值,用于生成schema,T为struct{}时返回nil
+func zeroValue[T any]() interface{} {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t == emptyStructType {
+		return nil
+	}
+	return reflect.Zero(indirectType(t)).Interface()
+}
+
+// typedValue 把binder解码出的指针转换为T,没有值时返回T的零值,T为指针时返回指向零值的指针
+func typedValue[T any](v interface{}) T {
+	switch v := v.(type) {
+	case T:
+		if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			return v
+		}
+	case *T:
+		return *v
+	}
+	var zero T
+	if t := reflect.TypeOf(zero); t != nil && t.Kind() == reflect.Ptr {
+		return reflect.New(t.Elem()).Interface().(T)
+	}
+	return zero
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type UserPara struct {
+	ID      int    `json:"id" in:"path"`
+	Verbose bool   `json:"verbose,omitempty" in:"query"`
+	Trace   string `json:"X-Trace,omitempty" in:"header"`
+}
+
+type UserBody struct {
+	Name string `json:"name" openapi:"minLen=1"`
+}
+
+type UserResp struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type NotFound struct {
+	Message string `json:"message"`
+}
+
+func TestHandle(t *testing.T) {
+	router := NewRouter(NewDocument("users", "1.0.0"), nil)
+	err := Handle(router, "POST", "/users/{id}", func(ctx context.Context, p UserPara, b *UserBody) (UserResp, error) {
+		if p.ID == 404 {
+			return UserResp{}, NewProblem(http.StatusNotFound, "no such user")
+		}
+		return UserResp{ID: p.ID, Name: b.Name}, nil
+	},
+		WithRouteSummary("update user"),
+		WithRouteTags("users"),
+		WithRouteOperationID("updateUser"),
+		WithRouteResponse(http.StatusNotFound, NotFound{}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, Handle(router, "DELETE", "/users/{id}", func(ctx context.Context, p UserPara, b struct{}) (struct{}, error) {
+		return struct{}{}, nil
+	}, WithRouteDeprecated()))
+
+	// 和手写RouteStruct生成的operation一样
+	doc := NewDocument("users", "1.0.0")
+	doc.Register(&MetaRouter{
+		TestRouter:  TestRouter{Method: "POST", Path: "/users/{id}", Param: UserPara{}, ReqStruct: UserBody{}},
+		Summary:     "update user",
+		Tags:        []string{"users"},
+		OperationID: "updateUser",
+	})
+	typed := router.Document().Snapshot().Paths["/users/{id}"].Post
+	plain := doc.Snapshot().Paths["/users/{id}"].Post
+	x, _ := json.Marshal(typed.Parameters)
+	y, _ := json.Marshal(plain.Parameters)
+	require.JSONEq(t, string(y), string(x))
+	x, _ = json.Marshal(typed.RequestBody)
+	y, _ = json.Marshal(plain.RequestBody)
+	require.JSONEq(t, string(y), string(x))
+	require.Equal(t, plain.Summary, typed.Summary)
+	require.Equal(t, plain.Tags, typed.Tags)
+	require.Contains(t, typed.Responses, "200")
+	require.Contains(t, typed.Responses, "404")
+
+	del := router.Document().Snapshot().Paths["/users/{id}"].Delete
+	require.True(t, del.Deprecated)
+	require.Nil(t, del.RequestBody)
+
+	req := httptest.NewRequest("POST", "/users/7", strings.NewReader(`{"name":"bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"id":7,"name":"bob"}`, rec.Body.String())
+
+	// 没有body时b是指向零值的指针
+	req = httptest.NewRequest("POST", "/users/8", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"id":8,"name":""}`, rec.Body.String())
+
+	req = httptest.NewRequest("POST", "/users/404", strings.NewReader(`{"name":"bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("DELETE", "/users/7", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	// R为指针时返回nil是handler的错误
+	require.NoError(t, Handle(router, "GET", "/users/{id}", func(ctx context.Context, p UserPara, b struct{}) (*UserResp, error) {
+		if p.ID == 0 {
+			return nil, nil
+		}
+		return &UserResp{ID: p.ID}, nil
+	}))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/users/7", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"id":7,"name":""}`, rec.Body.String())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/users/0", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+}
@@ -0,0 +1,463 @@
+// Package validate 用models.Schema校验json实例
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Chise1/openapi/models"
+)
+
+// ValidationError 一个校验失败的位置和原因
+type ValidationError struct {
+	Pointer string //实例中失败的位置,JSON pointer格式,例如/items/0/price
+	Keyword string //失败的schema关键字,例如required,maximum
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// Validator 用schema校验json实例,可以并发使用
+type Validator struct {
+	schemas  map[string]*models.Schema //components.schemas,用于解析$ref
+	patterns sync.Map                  //pattern -> *regexp.Regexp
+}
+
+// New 创建Validator,schemas为components.schemas,用于解析$ref
+func New(schemas map[string]*models.Schema) *Validator {
+	return &Validator{schemas: schemas}
+}
+
+// Validate 用schema校验instance,schemas为components.schemas,用于解析$ref.
+// 需要多次校验时使用New创建的Validator,可以复用编译好的正则表达式
+func Validate(schema *models.Schema, instance interface{}, schemas map[string]*models.Schema) []ValidationError {
+	return New(schemas).Validate(schema, instance)
+}
+
+// Validate 校验instance,instance为json解码后的值:
+// nil,bool,float64,json.Number,string,[]interface{},map[string]interface{},
+// 也接受go的整数和浮点数类型
+func (n *Validator) Validate(schema *models.Schema, instance interface{}) []ValidationError {
+	s := &state{validator: n}
+	s.validate(schema, instance, "", 0)
+	return s.errs
+}
+
+type state struct {
+	validator *Validator
+	errs      []ValidationError
+}
+
+// maxDepth 防止$ref循环引用导致无限递归
+const maxDepth = 64
+
+func (n *state) fail(pointer, keyword, format string, args ...interface{}) {
+	n.errs = append(n.errs, ValidationError{
+		Pointer: pointer,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (n *state) validate(schema *models.Schema, instance interface{}, pointer string, depth int) {
+	if schema == nil {
+		return
+	}
+	if depth > maxDepth {
+		n.fail(pointer, "$ref", "schema nesting is too deep")
+		return
+	}
+	if schema.Ref != "" {
+		ref, ok := n.validator.resolve(schema.Ref)
+		if !ok {
+			n.fail(pointer, "$ref", "unresolvable reference %q", schema.Ref)
+			return
+		}
+		n.validate(ref, instance, pointer, depth+1)
+		return
+	}
+	if instance == nil && schema.Nullable {
+		return
+	}
+	if schema.Type != "" && !isType(instance, schema.Type) {
+		n.fail(pointer, "type", "must be %s, got %s", schema.Type, typeOf(instance))
+		return
+	}
+	if len(schema.Enum) > 0 {
+		n.enum(schema, instance, pointer)
+	}
+	n.combinators(schema, instance, pointer, depth)
+	switch v := instance.(type) {
+	case string:
+		n.string(schema, v, pointer)
+	case []interface{}:
+		n.array(schema, v, pointer, depth)
+	case map[string]interface{}:
+		n.object(schema, v, pointer, depth)
+	default:
+		if f, ok := toFloat(instance); ok {
+			n.number(schema, f, pointer)
+		}
+	}
+}
+
+func (n *state) enum(schema *models.Schema, instance interface{}, pointer string) {
+	for _, e := range schema.Enum {
+		if equal(e, instance) {
+			return
+		}
+	}
+	n.fail(pointer, "enum", "must be one of %v", schema.Enum)
+}
+
+// combinators 校验allOf,anyOf,oneOf和not
+func (n *state) combinators(schema *models.Schema, instance interface{}, pointer string, depth int) {
+	for _, sub := range schema.AllOf {
+		n.validate(sub, instance, pointer, depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		if matched, errs := n.branches(schema.AnyOf, instance, pointer, depth); matched == 0 {
+			n.mismatch(errs, pointer, "anyOf", "must match at least one schema in anyOf")
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		matched, errs := n.branches(schema.OneOf, instance, pointer, depth)
+		if matched == 0 {
+			n.mismatch(errs, pointer, "oneOf", "must match exactly one schema in oneOf")
+		} else if matched > 1 {
+			n.fail(pointer, "oneOf", "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if schema.Not != nil {
+		sub := &state{validator: n.validator}
+		sub.validate(schema.Not, instance, pointer, depth+1)
+		if len(sub.errs) == 0 {
+			n.fail(pointer, "not", "must not match the schema in not")
+		}
+	}
+}
+
+// branches 用每个子schema校验instance,返回通过的数量和每个子schema的错误
+func (n *state) branches(schemas []*models.Schema, instance interface{}, pointer string, depth int) (int, [][]ValidationError) {
+	matched := 0
+	errs := make([][]ValidationError, len(schemas))
+	for i, sub := range schemas {
+		s := &state{validator: n.validator}
+		s.validate(sub, instance, pointer, depth+1)
+		if len(s.errs) == 0 {
+			matched++
+		}
+		errs[i] = s.errs
+	}
+	return matched, errs
+}
+
+// mismatch 所有子schema都不通过时,如果只有一个子schema的类型和instance一致,
+// 报告这个子schema的具体错误,例如可以为null的对象中某个字段不合法;否则报告message
+func (n *state) mismatch(errs [][]ValidationError, pointer, keyword, message string) {
+	var candidate []ValidationError
+	candidates := 0
+	for _, e := range errs {
+		if len(e) == 1 && e[0].Pointer == pointer && e[0].Keyword == "type" {
+			continue
+		}
+		candidate = e
+		candidates++
+	}
+	if candidates == 1 {
+		n.errs = append(n.errs, candidate...)
+		return
+	}
+	n.fail(pointer, keyword, message)
+}
+
+func (n *state) number(schema *models.Schema, f float64, pointer string) {
+	if schema.MultipleOf > 0 {
+		q := f / schema.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			n.fail(pointer, "multipleOf", "must be a multiple of %v", schema.MultipleOf)
+		}
+	}
+	if schema.Minimum != nil {
+		if schema.ExclusiveMinimum && f <= *schema.Minimum {
+			n.fail(pointer, "minimum", "must be greater than %v", *schema.Minimum)
+		} else if f < *schema.Minimum {
+			n.fail(pointer, "minimum", "must be greater than or equal to %v", *schema.Minimum)
+		}
+	}
+	if schema.Maximum != nil {
+		if schema.ExclusiveMaximum && f >= *schema.Maximum {
+			n.fail(pointer, "maximum", "must be less than %v", *schema.Maximum)
+		} else if f > *schema.Maximum {
+			n.fail(pointer, "maximum", "must be less than or equal to %v", *schema.Maximum)
+		}
+	}
+}
+
+func (n *state) string(schema *models.Schema, s string, pointer string) {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength > 0 && length < schema.MinLength {
+		n.fail(pointer, "minLength", "must be at least %d characters long", schema.MinLength)
+	}
+	if schema.MaxLength > 0 && length > schema.MaxLength {
+		n.fail(pointer, "maxLength", "must be at most %d characters long", schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := n.validator.pattern(schema.Pattern)
+		if err != nil {
+			n.fail(pointer, "pattern", "invalid pattern %q: %v", schema.Pattern, err)
+		} else if !re.MatchString(s) {
+			n.fail(pointer, "pattern", "must match pattern %q", schema.Pattern)
+		}
+	}
+}
+
+func (n *state) array(schema *models.Schema, a []interface{}, pointer string, depth int) {
+	if schema.MinItems > 0 && uint64(len(a)) < schema.MinItems {
+		n.fail(pointer, "minItems", "must have at least %d items", schema.MinItems)
+	}
+	if schema.MaxItems > 0 && uint64(len(a)) > schema.MaxItems {
+		n.fail(pointer, "maxItems", "must have at most %d items", schema.MaxItems)
+	}
+	if schema.UniqueItems {
+	unique:
+		for i := 1; i < len(a); i++ {
+			for j := 0; j < i; j++ {
+				if equal(a[i], a[j]) {
+					n.fail(pointer+"/"+strconv.Itoa(i), "uniqueItems", "duplicates item %d", j)
+					break unique
+				}
+			}
+		}
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range a {
+		n.validate(schema.Items, item, pointer+"/"+strconv.Itoa(i), depth+1)
+	}
+}
+
+func (n *state) object(schema *models.Schema, o map[string]interface{}, pointer string, depth int) {
+	if schema.MinProperties > 0 && uint64(len(o)) < schema.MinProperties {
+		n.fail(pointer, "minProperties", "must have at least %d properties", schema.MinProperties)
+	}
+	if schema.MaxProperties > 0 && uint64(len(o)) > schema.MaxProperties {
+		n.fail(pointer, "maxProperties", "must have at most %d properties", schema.MaxProperties)
+	}
+	for _, name := range schema.Required {
+		if _, ok := o[name]; !ok {
+			n.fail(pointer+"/"+escape(name), "required", "is required")
+		}
+	}
+	var properties map[string]*models.Schema
+	if schema.Properties != nil {
+		properties = make(map[string]*models.Schema, len(schema.Properties.Keys()))
+		for _, name := range schema.Properties.Keys() {
+			v, _ := schema.Properties.Get(name)
+			if property, ok := v.(*models.Schema); ok {
+				properties[name] = property
+			}
+		}
+	}
+	additional, allowed := additionalProperties(schema.AdditionalProperties)
+	for _, name := range sortedNames(o) {
+		value := o[name]
+		child := pointer + "/" + escape(name)
+		matched := false
+		if property, ok := properties[name]; ok {
+			n.validate(property, value, child, depth+1)
+			matched = true
+		}
+		for _, pattern := range sortedPatterns(schema.PatternProperties) {
+			re, err := n.validator.pattern(pattern)
+			if err != nil {
+				n.fail(child, "patternProperties", "invalid pattern %q: %v", pattern, err)
+				continue
+			}
+			if re.MatchString(name) {
+				n.validate(schema.PatternProperties[pattern], value, child, depth+1)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		if !allowed {
+			n.fail(child, "additionalProperties", "is not allowed")
+		} else if additional != nil {
+			n.validate(additional, value, child, depth+1)
+		}
+	}
+}
+
+func (n *Validator) resolve(ref string) (*models.Schema, bool) {
+	if !strings.HasPrefix(ref, models.REF_PREFIX) {
+		return nil, false
+	}
+	s, ok := n.schemas[strings.TrimPrefix(ref, models.REF_PREFIX)]
+	return s, ok && s != nil
+}
+
+func (n *Validator) pattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := n.patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	n.patterns.Store(pattern, re)
+	return re, nil
+}
+
+// additionalProperties 解析additionalProperties,返回额外属性的schema和是否允许额外属性
+func additionalProperties(raw json.RawMessage) (*models.Schema, bool) {
+	raw = bytes.TrimSpace(raw)
+	switch string(raw) {
+	case "", "true":
+		return nil, true
+	case "false":
+		return nil, false
+	}
+	s := &models.Schema{}
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, true
+	}
+	return s, true
+}
+
+func isType(instance interface{}, typ string) bool {
+	switch typ {
+	case "null":
+		return instance == nil
+	case "boolean":
+		_, ok := instance.(bool)
+		return ok
+	case "string":
+		_, ok := instance.(string)
+		return ok
+	case "array":
+		_, ok := instance.([]interface{})
+		return ok
+	case "object":
+		_, ok := instance.(map[string]interface{})
+		return ok
+	case "number":
+		_, ok := toFloat(instance)
+		return ok
+	case "integer":
+		f, ok := toFloat(instance)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+	return true
+}
+
+func typeOf(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat(instance); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func toFloat(instance interface{}) (float64, bool) {
+	switch v := instance.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	rv := reflect.ValueOf(instance)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// equal 按json的语义比较两个值,数字按数值比较
+func equal(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	switch av := a.(type) {
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if w, ok := bv[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// escape 按RFC 6901转义JSON pointer中的一段
+func escape(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func sortedPatterns(m map[string]*models.Schema) []string {
+	patterns := make([]string, 0, len(m))
+	for pattern := range m {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+func sortedNames(o map[string]interface{}) []string {
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func decode(t *testing.T, s string) interface{} {
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	item := orderedmap.New()
+	item.Set("sku", &models.Schema{Type: "string", Pattern: "^[A-Z]+$"})
+	item.Set("price", &models.Schema{Type: "number", Minimum: float(0), ExclusiveMinimum: true, MultipleOf: 0.5})
+	order := orderedmap.New()
+	order.Set("items", &models.Schema{
+		Type:        "array",
+		MinItems:    1,
+		MaxItems:    3,
+		UniqueItems: true,
+		Items:       &models.Schema{Ref: "#/components/schemas/Item"},
+	})
+	order.Set("coupon", &models.Schema{OneOf: []*models.Schema{
+		{Ref: "#/components/schemas/Item"},
+		{Type: "null"},
+	}})
+	order.Set("note", &models.Schema{Type: "string", Nullable: true, Not: &models.Schema{Enum: []interface{}{"test"}}})
+	schemas := map[string]*models.Schema{
+		"Item": {Type: "object", Required: []string{"sku"}, Properties: item, AdditionalProperties: []byte("false")},
+		"Order": {
+			Type:          "object",
+			Properties:    order,
+			MaxProperties: 4,
+			PatternProperties: map[string]*models.Schema{
+				"^x-": {Type: "string"},
+			},
+			AdditionalProperties: []byte("false"),
+		},
+	}
+	schema := &models.Schema{Ref: "#/components/schemas/Order"}
+
+	require.Empty(t, Validate(schema, decode(t, `{"items":[{"sku":"A","price":1.5}],"coupon":null,"note":null,"x-trace":"1"}`), schemas))
+
+	errs := Validate(schema, decode(t, `{
+		"items":[{"sku":"a","price":0.7},{"sku":"a","price":0.7}],
+		"coupon":{"price":1},
+		"note":"test",
+		"x-trace":1,
+		"other":true
+	}`), schemas)
+	require.Equal(t, []ValidationError{
+		{Pointer: "", Keyword: "maxProperties", Message: "must have at most 4 properties"},
+		{Pointer: "/coupon/sku", Keyword: "required", Message: "is required"},
+		{Pointer: "/items/1", Keyword: "uniqueItems", Message: "duplicates item 0"},
+		{Pointer: "/items/0/price", Keyword: "multipleOf", Message: "must be a multiple of 0.5"},
+		{Pointer: "/items/0/sku", Keyword: "pattern", Message: `must match pattern "^[A-Z]+$"`},
+		{Pointer: "/items/1/price", Keyword: "multipleOf", Message: "must be a multiple of 0.5"},
+		{Pointer: "/items/1/sku", Keyword: "pattern", Message: `must match pattern "^[A-Z]+$"`},
+		{Pointer: "/note", Keyword: "not", Message: "must not match the schema in not"},
+		{Pointer: "/other", Keyword: "additionalProperties", Message: "is not allowed"},
+		{Pointer: "/x-trace", Keyword: "type", Message: "must be string, got number"},
+	}, errs)
+}
+
+func TestValidateCombinators(t *testing.T) {
+	schema := &models.Schema{
+		AllOf: []*models.Schema{{Type: "integer"}, {Minimum: float(10)}},
+		OneOf: []*models.Schema{{MultipleOf: 2}, {MultipleOf: 3}},
+		AnyOf: []*models.Schema{{Maximum: float(20)}, {Minimum: float(100)}},
+	}
+	require.Empty(t, Validate(schema, 14, nil))
+	require.Equal(t, []ValidationError{
+		{Keyword: "oneOf", Message: "must match exactly one schema in oneOf, matched 2"},
+	}, Validate(schema, 12, nil))
+	require.Equal(t, []ValidationError{
+		{Keyword: "anyOf", Message: "must match at least one schema in anyOf"},
+		{Keyword: "oneOf", Message: "must match exactly one schema in oneOf"},
+	}, Validate(schema, 55, nil))
+	require.Equal(t, []ValidationError{
+		{Keyword: "type", Message: "must be integer, got number"},
+		{Keyword: "minimum", Message: "must be greater than or equal to 10"},
+	}, Validate(schema, 2.5, nil)[:2])
+	require.Equal(t, []ValidationError{
+		{Pointer: "", Keyword: "$ref", Message: `unresolvable reference "#/components/schemas/Missing"`},
+	}, Validate(&models.Schema{Ref: "#/components/schemas/Missing"}, 1, nil))
+}
@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Chise1/openapi/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type YAMLBody struct {
+	Zebra string `json:"zebra" openapi_extras:"x-order=1"`
+	Apple int    `json:"apple,omitempty" openapi:"desc=first line"`
+	Mango []bool `json:"mango"`
+}
+
+func TestWriteYAML(t *testing.T) {
+	doc := NewDocument("yaml", "1.0.0", WithDescription("line one\nline two"))
+	doc.Register(&TestRouter{Method: "POST", Path: "/fruits", ReqStruct: YAMLBody{}})
+	var buf bytes.Buffer
+	require.NoError(t, doc.Snapshot().WriteYAML(&buf))
+	out := buf.String()
+
+	require.Contains(t, out, "info:\n  title: yaml\n  description: |-\n    line one\n    line two\n  version: 1.0.0\n")
+	require.Contains(t, out, `
+    YAMLBody:
+      type: object
+      required:
+        - zebra
+        - mango
+      properties:
+        zebra:
+          type: string
+          title: Zebra
+          x-order: "1"
+        apple:
+`)
+	require.Less(t, strings.Index(out, "apple:"), strings.Index(out, "mango:"))
+	require.Contains(t, out, `"200":`)
+	require.NotContains(t, out, "{\"")
+
+	var spec map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &spec))
+	require.Equal(t, "3.0.2", spec["openapi"])
+
+	b, err := yaml.Marshal(&models.Schema{Type: "object", Properties: doc.Snapshot().Components.Schemas["YAMLBody"].Properties})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(b), "type: object\nproperties:\n    zebra:\n"), string(b))
+}